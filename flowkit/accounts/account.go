@@ -19,14 +19,24 @@
 package accounts
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/crypto"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/config/json"
+	"github.com/onflow/flow-cli/flowkit/gateway"
 )
 
 // Account is defined by an address and name and contains an Key which can be used for signing.
@@ -34,57 +44,415 @@ type Account struct {
 	Name    string
 	Address flow.Address
 	Key     Key
+	// Labels annotate the account with metadata such as the owner or the purpose, they are not used by flowkit.
+	Labels map[string]string
+	// RoleKeyIndices maps transaction roles to the index used by the key for the role, which allows signing
+	// with the same key registered at multiple indices, e.g. a dedicated proposer key.
+	RoleKeyIndices map[SignRole]int
+	// SigningPolicy is consulted before signing transactions with the account, it is not stored in the configuration.
+	SigningPolicy SigningPolicy
 }
 
+// NewDeferredAddressAccount creates an account whose address is not known until the account is created,
+// such as an emulator account, the address must be set with SetAddress before signing transactions.
+func NewDeferredAddressAccount(name string, key Key) *Account {
+	return &Account{
+		Name: name,
+		Key:  key,
+	}
+}
+
+// SetAddress sets the address of an account created with a deferred address.
+func (a *Account) SetAddress(address flow.Address) error {
+	if address == flow.EmptyAddress {
+		return fmt.Errorf("can not set an empty address for account %s", a.Name)
+	}
+	if a.Address != flow.EmptyAddress && a.Address != address {
+		return fmt.Errorf("account %s already has the address %s", a.Name, a.Address)
+	}
+	a.Address = address
+	return nil
+}
+
+// HasAddress returns whether the address of the account is set.
+func (a *Account) HasAddress() bool {
+	return a.Address != flow.EmptyAddress
+}
+
+// Label returns the value of the label and whether the label is set.
+func (a *Account) Label(key string) (string, bool) {
+	value, ok := a.Labels[key]
+	return value, ok
+}
+
+// SetLabel sets the label to the value.
+func (a *Account) SetLabel(key string, value string) {
+	if a.Labels == nil {
+		a.Labels = make(map[string]string)
+	}
+	a.Labels[key] = value
+}
+
+// FormattedAddress returns the address as 0x prefixed hex, or an empty string if the account has no address.
+func (a *Account) FormattedAddress() string {
+	if a.Address == flow.EmptyAddress {
+		return ""
+	}
+	return "0x" + a.Address.Hex()
+}
+
+// ShortAddress returns the address truncated for display, such as 0xf8d6...20c7,
+// or an empty string if the account has no address.
+func (a *Account) ShortAddress() string {
+	if a.Address == flow.EmptyAddress {
+		return ""
+	}
+	address := a.Address.Hex()
+	return fmt.Sprintf("0x%s...%s", address[:4], address[len(address)-4:])
+}
+
+// SignRole is the role in which an account signs a transaction.
+type SignRole int
+
+const (
+	SignRoleProposer SignRole = iota
+	SignRoleAuthorizer
+	SignRolePayer
+)
+
+var signRoleNames = []string{"proposer", "authorizer", "payer"}
+
+func (r SignRole) String() string {
+	if int(r) < 0 || int(r) >= len(signRoleNames) {
+		return "unknown"
+	}
+	return signRoleNames[r]
+}
+
+func parseSignRole(name string) (SignRole, error) {
+	index := slices.Index(signRoleNames, name)
+	if index == -1 {
+		return 0, fmt.Errorf("invalid transaction role %s, must be one of %s", name, strings.Join(signRoleNames, ", "))
+	}
+	return SignRole(index), nil
+}
+
+// RoleKeyIndex returns the key index used for the role, which is the key index if the role is not mapped.
+func (a *Account) RoleKeyIndex(role SignRole) int {
+	if index, ok := a.RoleKeyIndices[role]; ok {
+		return index
+	}
+	return a.Key.Index()
+}
+
+// RoleSigner returns the signer and the key index used to sign for the role.
+func (a *Account) RoleSigner(ctx context.Context, role SignRole) (crypto.Signer, int, error) {
+	if a.Key == nil {
+		return nil, 0, fmt.Errorf("account %s is missing the key", a.Name)
+	}
+
+	signer, err := a.Key.Signer(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return signer, a.RoleKeyIndex(role), nil
+}
+
+// SignTransaction signs the transaction with the account key and attaches the signature for the role.
+//
+// The payer signs the transaction envelope, while the proposer and authorizers sign the payload.
+// The signing policy of the account is checked before the key signer is used.
+func (a *Account) SignTransaction(ctx context.Context, tx *flow.Transaction, role SignRole) error {
+	if err := a.checkSigning(tx, role); err != nil {
+		return err
+	}
+
+	signer, index, err := a.RoleSigner(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	return a.attachSignature(tx, role, index, signer)
+}
+
+// checkSigning checks the signing policy allows signing the transaction for the role, the key is set
+// with a nonzero weight and the address is set, before any key signer is used.
+func (a *Account) checkSigning(tx *flow.Transaction, role SignRole) error {
+	if a.SigningPolicy != nil {
+		if err := a.SigningPolicy(tx, role); err != nil {
+			return fmt.Errorf("signing policy of account %s denied signing as %s: %w", a.Name, role, err)
+		}
+	}
+
+	if a.Key == nil {
+		return fmt.Errorf("account %s is missing the key", a.Name)
+	}
+
+	if err := a.checkNonzeroWeight(); err != nil {
+		return err
+	}
+
+	if !a.HasAddress() {
+		return fmt.Errorf("address of account %s is not set, set it with SetAddress once the account is created", a.Name)
+	}
+
+	return nil
+}
+
+// checkNonzeroWeight checks the account key has a nonzero weight, a signature of a key with zero weight,
+// such as a key left behind by a failed rotation, can never authorize the transaction.
+//
+// A key without a weight in the configuration has the full weight, while a weight explicitly set to zero fails.
+func (a *Account) checkNonzeroWeight() error {
+	if a.Key != nil && a.Key.Weight() <= 0 {
+		return fmt.Errorf("account %s has no keys with nonzero weight", a.Name)
+	}
+	return nil
+}
+
+// attachSignature signs the transaction with the signer of the key at the index and attaches the signature for the role.
+func (a *Account) attachSignature(tx *flow.Transaction, role SignRole, index int, signer crypto.Signer) error {
+	var err error
+	if role == SignRolePayer {
+		err = tx.SignEnvelope(a.Address, index, signer)
+	} else {
+		err = tx.SignPayload(a.Address, index, signer)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SignedWeight returns the weight the account signed the transaction with for the role, which is the key
+// weight if the transaction has the signature of the role key index and zero otherwise.
+//
+// The payer weight is counted from the envelope signatures and the proposer and authorizer weight from the
+// payload signatures. Signatures at the key indices of other roles are made with the same local key,
+// so they don't add weight to the role.
+func (a *Account) SignedWeight(tx *flow.Transaction, role SignRole) int {
+	if a.Key == nil {
+		return 0
+	}
+
+	signatures := tx.PayloadSignatures
+	if role == SignRolePayer {
+		signatures = tx.EnvelopeSignatures
+	}
+
+	index := a.RoleKeyIndex(role)
+	for _, sig := range signatures {
+		if sig.Address == a.Address && sig.KeyIndex == index {
+			return a.Key.Weight()
+		}
+	}
+
+	return 0
+}
+
+// Validate checks the account and its key, returning an error describing all the problems found.
+func (a *Account) Validate() error {
+	return a.validate("")
+}
+
+// ValidateForChain checks the account and its key same as Validate and also checks the address is valid on the chain.
+func (a *Account) ValidateForChain(chain flow.ChainID) error {
+	return a.validate(chain)
+}
+
+func (a *Account) validate(chain flow.ChainID) error {
+	problems := make([]string, 0)
+
+	if a.Name == "" {
+		problems = append(problems, "missing name")
+	}
+
+	if a.Address == flow.EmptyAddress {
+		problems = append(problems, "missing address")
+	} else if chain != "" && !a.Address.IsValid(chain) {
+		problems = append(problems, fmt.Sprintf("address %s is not valid on %s", a.Address, chain))
+	}
+
+	if a.Key == nil {
+		problems = append(problems, "missing key")
+	} else {
+		if a.Key.Index() < 0 {
+			problems = append(problems, fmt.Sprintf("invalid key index %d", a.Key.Index()))
+		}
+		if err := a.Key.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid key: %s", err))
+		}
+	}
+
+	for _, role := range []SignRole{SignRoleProposer, SignRoleAuthorizer, SignRolePayer} {
+		if index, ok := a.RoleKeyIndices[role]; ok && index < 0 {
+			problems = append(problems, fmt.Sprintf("invalid %s key index %d", role, index))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("account %s is invalid: %s", a.Name, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// FromConfig loads the accounts from the configuration.
+//
+// Keys derived from the mnemonic of a BIP44 key, as configured by its derivations, are loaded as separate accounts
+// following the account of the BIP44 key.
 func FromConfig(conf *config.Config) (Accounts, error) {
 	var accounts Accounts
 	for _, accountConf := range conf.Accounts {
-		acc, err := fromConfig(accountConf)
+		accs, err := fromConfigWithDerived(accountConf)
 		if err != nil {
 			return nil, err
 		}
-		accounts = append(accounts, *acc)
+		accounts = append(accounts, accs...)
 	}
 
 	return accounts, nil
 }
 
+// FromConfigLenient loads all the valid accounts from the configuration and returns
+// the errors of the accounts that couldn't be loaded separately.
+//
+// Unlike FromConfig one invalid account doesn't prevent using the rest of the accounts.
+func FromConfigLenient(conf *config.Config) (Accounts, []error) {
+	var accounts Accounts
+	var errs []error
+	for _, accountConf := range conf.Accounts {
+		accs, err := fromConfigWithDerived(accountConf)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid account %s: %w", accountConf.Name, err))
+			continue
+		}
+		accounts = append(accounts, accs...)
+	}
+
+	return accounts, errs
+}
+
+// ToConfig converts the accounts to the configuration, accounts with keys derived from the
+// mnemonic of another BIP44 key are saved as the derivations of that key.
 func ToConfig(accounts Accounts) config.Accounts {
 	accountConfs := make([]config.Account, 0)
 
 	for _, account := range accounts {
+		if key, ok := account.Key.(*BIP44Key); ok && key.derived {
+			continue
+		}
 		accountConfs = append(accountConfs, toConfig(account))
 	}
 
 	return accountConfs
 }
 
+// fromConfigWithDerived loads the account followed by the accounts with keys derived from the mnemonic of its key.
+func fromConfigWithDerived(account config.Account) ([]Account, error) {
+	acc, err := fromConfig(account)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := acc.Key.(*BIP44Key)
+	if !ok {
+		return []Account{*acc}, nil
+	}
+
+	derived, err := key.derivedAccounts(acc.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Account{*acc}, derived...), nil
+}
+
 func fromConfig(account config.Account) (*Account, error) {
 	key, err := keyFromConfig(account.Key)
 	if err != nil {
 		return nil, err
 	}
 
+	var roleKeyIndices map[SignRole]int
+	for name, index := range account.RoleKeyIndices {
+		role, err := parseSignRole(name)
+		if err != nil {
+			return nil, err
+		}
+		if roleKeyIndices == nil {
+			roleKeyIndices = make(map[SignRole]int)
+		}
+		roleKeyIndices[role] = index
+	}
+
 	return &Account{
-		Name:    account.Name,
-		Address: account.Address,
-		Key:     key,
+		Name:           account.Name,
+		Address:        account.Address,
+		Key:            key,
+		Labels:         maps.Clone(account.Labels),
+		RoleKeyIndices: roleKeyIndices,
 	}, nil
 }
 
+// MarshalConfigJSON returns the account in the JSON format used in flow.json,
+// as an object keyed by the account name which can be added to the accounts section.
+func (a *Account) MarshalConfigJSON() ([]byte, error) {
+	return json.NewParser().SerializeAccounts(config.Accounts{toConfig(*a)})
+}
+
 func toConfig(account Account) config.Account {
 	var key config.AccountKey
 	if account.Key != nil {
 		key = account.Key.ToConfig()
 	}
 
+	var roleKeyIndices map[string]int
+	for role, index := range account.RoleKeyIndices {
+		if roleKeyIndices == nil {
+			roleKeyIndices = make(map[string]int)
+		}
+		roleKeyIndices[role.String()] = index
+	}
+
 	return config.Account{
-		Name:    account.Name,
-		Address: account.Address,
-		Key:     key,
+		Name:           account.Name,
+		Address:        account.Address,
+		Key:            key,
+		Labels:         maps.Clone(account.Labels),
+		RoleKeyIndices: roleKeyIndices,
 	}
 }
 
+// NewAccountFromAddressAndKey creates an unnamed account with the address and a hex key at index 0.
+//
+// The hash algorithm must be the one the key is registered with on the network, since signatures
+// made with a different hash algorithm don't verify.
+func NewAccountFromAddressAndKey(
+	address flow.Address,
+	privateKey crypto.PrivateKey,
+	hashAlgo crypto.HashAlgorithm,
+) (*Account, error) {
+	if !crypto.CompatibleAlgorithms(privateKey.Algorithm(), hashAlgo) {
+		return nil, fmt.Errorf("invalid hash algorithm %s for signature algorithm %s", hashAlgo, privateKey.Algorithm())
+	}
+
+	return &Account{
+		Address: address,
+		Key:     NewHexKeyFromPrivateKey(0, hashAlgo, privateKey),
+	}, nil
+}
+
+// PredictAccountAddress returns the address the account at the index will have on the chain.
+//
+// Addresses are assigned sequentially as accounts are created, starting with the service account at index 1,
+// which allows pre-allocating addresses for accounts that are about to be created on a fresh network.
+func PredictAccountAddress(chain flow.ChainID, index uint64) flow.Address {
+	return flow.NewAddressGenerator(chain).SetIndex(uint(index)).Address()
+}
+
 func NewEmulatorAccount(sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) (*Account, error) {
 	seed := make([]byte, crypto.MinSeedLength)
 	_, err := rand.Read(seed)
@@ -104,6 +472,190 @@ func NewEmulatorAccount(sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashA
 	}, nil
 }
 
+// NewEmulatorAccountFromMnemonic creates the emulator service account with a key derived from the mnemonic.
+//
+// Unlike NewEmulatorAccount the key is deterministic, so the same mnemonic results in the same
+// emulator service account on every machine. If the derivation path is not provided a default "m/44'/539'/0'/0/0" will be used.
+func NewEmulatorAccountFromMnemonic(
+	mnemonic string,
+	derivationPath string,
+	sigAlgo crypto.SignatureAlgorithm,
+	hashAlgo crypto.HashAlgorithm,
+) (*Account, error) {
+	key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        sigAlgo,
+		HashAlgo:       hashAlgo,
+		Mnemonic:       mnemonic,
+		DerivationPath: derivationPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = key.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive emulator service key: %w", err)
+	}
+
+	return &Account{
+		Name:    config.DefaultEmulator.ServiceAccount,
+		Address: flow.ServiceAddress(flow.Emulator),
+		Key:     key,
+	}, nil
+}
+
+// CanAuthorizeAlone returns whether the weight of the local key reaches the weight threshold
+// required to sign for the account, otherwise the transaction needs additional signers.
+//
+// The weight is the one defined in the configuration, which should match the key weight on the network.
+func (a *Account) CanAuthorizeAlone() (bool, error) {
+	if a.Key == nil {
+		return false, fmt.Errorf("account %s has no key", a.Name)
+	}
+
+	weight := a.Key.Weight()
+	if weight < 0 || weight > flow.AccountKeyWeightThreshold {
+		return false, fmt.Errorf("invalid weight %d of the key for account %s", weight, a.Name)
+	}
+
+	return weight >= flow.AccountKeyWeightThreshold, nil
+}
+
+// EmulatorAccountOptions are the options used to generate an emulator account with GenerateEmulatorAccount.
+type EmulatorAccountOptions struct {
+	// Name of the account, defaults to the emulator service account name.
+	Name string
+	// Address of the account, defaults to the emulator service address.
+	Address flow.Address
+	// Keys is the number of generated keys, defaults to one key.
+	Keys int
+	// Weights of the keys by key index, a missing or zero weight is the full weight.
+	Weights []int
+	// SigAlgo and HashAlgo of the keys, default to the default algorithms.
+	SigAlgo  crypto.SignatureAlgorithm
+	HashAlgo crypto.HashAlgorithm
+	// Seed makes the generated keys deterministic, if not provided the keys are random.
+	Seed []byte
+}
+
+// GenerateEmulatorAccount generates an emulator account with the keys described by the options.
+//
+// An account signs using a single key, so the returned account uses the key at index 0 and all generated keys,
+// including the first one, are returned in key index order to be added to the account on the network.
+func GenerateEmulatorAccount(opts EmulatorAccountOptions) (*Account, []Key, error) {
+	if opts.Name == "" {
+		opts.Name = config.DefaultEmulator.ServiceAccount
+	}
+	if opts.Address == flow.EmptyAddress {
+		opts.Address = flow.ServiceAddress(flow.Emulator)
+	}
+	if opts.Keys == 0 {
+		opts.Keys = 1
+	}
+	defaultSigAlgo, defaultHashAlgo := config.DefaultAlgorithms()
+	if opts.SigAlgo == crypto.UnknownSignatureAlgorithm {
+		opts.SigAlgo = defaultSigAlgo
+	}
+	if opts.HashAlgo == crypto.UnknownHashAlgorithm {
+		opts.HashAlgo = defaultHashAlgo
+	}
+
+	if opts.Keys < 0 {
+		return nil, nil, fmt.Errorf("invalid number of keys %d", opts.Keys)
+	}
+	if len(opts.Weights) > opts.Keys {
+		return nil, nil, fmt.Errorf("%d weights provided for %d keys", len(opts.Weights), opts.Keys)
+	}
+	if opts.Seed != nil && len(opts.Seed) < crypto.MinSeedLength {
+		return nil, nil, fmt.Errorf("seed must be at least %d bytes long", crypto.MinSeedLength)
+	}
+
+	keys := make([]Key, opts.Keys)
+	for i := range keys {
+		seed := make([]byte, crypto.MinSeedLength)
+		if opts.Seed != nil {
+			// derive a different seed for each key index
+			index := make([]byte, 4)
+			binary.BigEndian.PutUint32(index, uint32(i))
+			digest := sha256.Sum256(append(index, opts.Seed...))
+			seed = digest[:]
+		} else if _, err := rand.Read(seed); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate random seed: %v", err)
+		}
+
+		privateKey, err := crypto.GeneratePrivateKey(opts.SigAlgo, seed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate emulator account key: %v", err)
+		}
+
+		key := NewHexKeyFromPrivateKey(i, opts.HashAlgo, privateKey)
+		if i < len(opts.Weights) {
+			if opts.Weights[i] < 0 || opts.Weights[i] > flow.AccountKeyWeightThreshold {
+				return nil, nil, fmt.Errorf("invalid weight %d of key %d", opts.Weights[i], i)
+			}
+			key.weight = opts.Weights[i]
+		}
+		keys[i] = key
+	}
+
+	return &Account{
+		Name:    opts.Name,
+		Address: opts.Address,
+		Key:     keys[0],
+	}, keys, nil
+}
+
+// defaultVanityAttempts is the number of account indices checked for a vanity address if no limit is provided.
+const defaultVanityAttempts = 1000
+
+// GenerateVanityEmulatorAccount generates an emulator account whose address matches the pattern, such as "^f3"
+// for addresses starting with f3, checked against the hex address without the 0x prefix.
+//
+// Emulator addresses are assigned sequentially, so the account indices are checked in order starting with
+// the service account, and the index of the matching account is returned, the address is only assigned once
+// all the accounts before the index are created. If the options don't provide a seed the keys are generated
+// from a seed derived from the address, so the same account is generated on every run.
+//
+// At most maxAttempts indices are checked, defaulting to 1000, before an error is returned.
+func GenerateVanityEmulatorAccount(
+	pattern string,
+	maxAttempts int,
+	opts EmulatorAccountOptions,
+) (*Account, []Key, uint64, error) {
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("invalid vanity address pattern %s: %w", pattern, err)
+	}
+	if maxAttempts < 0 {
+		return nil, nil, 0, fmt.Errorf("invalid number of attempts %d", maxAttempts)
+	}
+	if maxAttempts == 0 {
+		maxAttempts = defaultVanityAttempts
+	}
+
+	for index := uint64(1); index <= uint64(maxAttempts); index++ {
+		address := PredictAccountAddress(flow.Emulator, index)
+		if !matcher.MatchString(address.Hex()) {
+			continue
+		}
+
+		opts.Address = address
+		if opts.Seed == nil {
+			seed := sha256.Sum256(address.Bytes())
+			opts.Seed = seed[:]
+		}
+
+		account, keys, err := GenerateEmulatorAccount(opts)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		return account, keys, index, nil
+	}
+
+	return nil, nil, 0, fmt.Errorf("no emulator address matching %s found in %d attempts", pattern, maxAttempts)
+}
+
 // Accounts is a collection of account.
 type Accounts []Account
 
@@ -150,6 +702,19 @@ func (a Accounts) ByAddress(address flow.Address) (*Account, error) {
 	return nil, fmt.Errorf("could not find account with address %s in the configuration", address)
 }
 
+// FindSigner returns the signer of the account key with the address and key index.
+//
+// It's useful when only the address of a signer is known, since multiple accounts can share an address using different keys.
+func (a Accounts) FindSigner(ctx context.Context, address flow.Address, keyIndex int) (crypto.Signer, error) {
+	for i := range a {
+		if a[i].Address == address && a[i].Key != nil && a[i].Key.Index() == keyIndex {
+			return a[i].Key.Signer(ctx)
+		}
+	}
+
+	return nil, fmt.Errorf("could not find account with address %s and key index %d in the configuration", address, keyIndex)
+}
+
 // ByName get an account by name or returns and error if no account found
 func (a Accounts) ByName(name string) (*Account, error) {
 	for i := range a {
@@ -172,3 +737,630 @@ func (a *Accounts) AddOrUpdate(account *Account) {
 
 	*a = append(*a, *account)
 }
+
+// DerivationIndices returns the sorted derivation indices already used by BIP44 keys derived from
+// the provided mnemonic and suggests the index following the highest used index for a new account.
+// Gaps left by removed accounts are not reused, since their keys may still be registered on chain.
+//
+// The names of the accounts with BIP44 keys whose mnemonic is encrypted or prompted and not loaded yet
+// are returned as undetermined, since the keys may use the mnemonic and the suggested index.
+//
+// The derivation index is the last component of the derivation path (m/44'/539'/0'/0/index).
+func (a *Accounts) DerivationIndices(mnemonic string) (indices []uint32, next uint32, undetermined []string, err error) {
+	mnemonic = NormalizeMnemonic(mnemonic)
+	indices = make([]uint32, 0)
+	undetermined = make([]string, 0)
+	for _, acc := range *a {
+		key, ok := acc.Key.(*BIP44Key)
+		if !ok {
+			continue
+		}
+
+		loaded := key.loadedMnemonic()
+		if loaded == "" {
+			undetermined = append(undetermined, acc.Name)
+			continue
+		}
+		if NormalizeMnemonic(loaded) != mnemonic {
+			continue
+		}
+
+		path, err := key.parsedPath()
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("invalid derivation path defined for account %s", acc.Name)
+		}
+
+		index := path[len(path)-1]
+		if !slices.Contains(indices, index) {
+			indices = append(indices, index)
+		}
+	}
+
+	slices.Sort(indices)
+	if len(indices) > 0 {
+		next = indices[len(indices)-1] + 1
+	}
+
+	return indices, next, undetermined, nil
+}
+
+// DetectDuplicateKeys returns an error if any two accounts with different addresses use the same key.
+//
+// Keys are compared by their public keys, and remote keys without an accessible private key are compared by the resource ID.
+// Keys without an obtainable public key, such as encrypted keys without a passphrase, are skipped.
+func (a *Accounts) DetectDuplicateKeys() error {
+	owners := make(map[string]Account)
+	duplicates := make([]string, 0)
+
+	for _, acc := range *a {
+		if acc.Key == nil {
+			continue
+		}
+
+		fingerprint, err := keyFingerprint(acc.Key)
+		if err != nil {
+			continue
+		}
+
+		owner, ok := owners[fingerprint]
+		if !ok {
+			owners[fingerprint] = acc
+			continue
+		}
+
+		if owner.Address != acc.Address {
+			duplicates = append(duplicates, fmt.Sprintf("%s and %s", owner.Name, acc.Name))
+		}
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("accounts use duplicate keys: %s", strings.Join(duplicates, ", "))
+	}
+
+	return nil
+}
+
+// Validate checks every account same as Account.Validate and also checks accounts sharing an address
+// use different key indices, returning an error describing all the problems found.
+func (a *Accounts) Validate() error {
+	return a.validate("")
+}
+
+// ValidateForChain checks the accounts same as Validate and also checks the addresses are valid on the chain.
+func (a *Accounts) ValidateForChain(chain flow.ChainID) error {
+	return a.validate(chain)
+}
+
+func (a *Accounts) validate(chain flow.ChainID) error {
+	type addressIndex struct {
+		address flow.Address
+		index   int
+	}
+
+	problems := make([]string, 0)
+	owners := make(map[addressIndex]string)
+
+	for _, acc := range *a {
+		if err := acc.validate(chain); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if acc.Key == nil || acc.Address == flow.EmptyAddress {
+			continue
+		}
+
+		key := addressIndex{address: acc.Address, index: acc.Key.Index()}
+		if owner, ok := owners[key]; ok {
+			problems = append(problems, fmt.Sprintf(
+				"accounts %s and %s use the same key index %d of address %s",
+				owner, acc.Name, key.index, key.address,
+			))
+			continue
+		}
+		owners[key] = acc.Name
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("accounts are invalid: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// MergeAccounts merges two accounts with the same address, such as an account from a base configuration
+// and the same account from an override, where values of the second account take precedence.
+//
+// Labels, role key indices and signing policies are merged, and a key defined by only one of the accounts is used. If both accounts
+// define a key at the same index the keys must match, keys without an obtainable public key, such as encrypted keys
+// without a passphrase, match if their configuration is the same. An account holds a single key, so keys at different
+// indices can not be merged and result in an error.
+func MergeAccounts(a, b *Account) (*Account, error) {
+	if a.Address != b.Address {
+		return nil, fmt.Errorf("can not merge accounts %s and %s with different addresses", a.Name, b.Name)
+	}
+
+	merged := &Account{
+		Name:    a.Name,
+		Address: a.Address,
+		Key:     a.Key,
+	}
+	if b.Name != "" {
+		merged.Name = b.Name
+	}
+
+	if a.Key == nil {
+		merged.Key = b.Key
+	} else if b.Key != nil {
+		if a.Key.Index() != b.Key.Index() {
+			return nil, fmt.Errorf(
+				"can not merge keys at indices %d and %d of account %s, an account holds a single key",
+				a.Key.Index(),
+				b.Key.Index(),
+				merged.Name,
+			)
+		}
+
+		// keys without an obtainable public key must have the same configuration
+		first, errFirst := keyFingerprint(a.Key)
+		second, errSecond := keyFingerprint(b.Key)
+		if errFirst != nil || errSecond != nil {
+			first, second = keyConfigFingerprint(a.Key), keyConfigFingerprint(b.Key)
+		}
+		if first != second {
+			return nil, fmt.Errorf("conflicting keys at index %d of account %s", a.Key.Index(), merged.Name)
+		}
+	}
+
+	for k, v := range a.Labels {
+		merged.SetLabel(k, v)
+	}
+	for k, v := range b.Labels {
+		merged.SetLabel(k, v)
+	}
+
+	if len(a.RoleKeyIndices) > 0 || len(b.RoleKeyIndices) > 0 {
+		merged.RoleKeyIndices = make(map[SignRole]int)
+		maps.Copy(merged.RoleKeyIndices, a.RoleKeyIndices)
+		maps.Copy(merged.RoleKeyIndices, b.RoleKeyIndices)
+	}
+
+	if a.SigningPolicy != nil || b.SigningPolicy != nil {
+		merged.SigningPolicy = AllPolicies(a.SigningPolicy, b.SigningPolicy)
+	}
+
+	return merged, nil
+}
+
+// AccountsFingerprint returns a digest of the accounts which changes when any account name, address or key changes.
+//
+// The digest doesn't depend on the order of the accounts and is computed from the key configuration only, keys are
+// never loaded so reading key files, decrypting keys, prompting for secrets or reaching remote services never happens.
+func AccountsFingerprint(accounts []*Account) string {
+	entries := make([]string, 0, len(accounts))
+	for _, acc := range accounts {
+		if acc == nil {
+			continue
+		}
+
+		entry := []string{acc.Name, acc.Address.String()}
+		if acc.Key != nil {
+			entry = append(entry, keyConfigFingerprint(acc.Key))
+		}
+		entries = append(entries, strings.Join(entry, "\x00"))
+	}
+	slices.Sort(entries)
+
+	digest := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(digest[:])
+}
+
+// keyConfigFingerprint returns a digest of the key configuration without loading the key.
+//
+// Private keys in the configuration are represented by their public keys, while mnemonics and encrypted
+// secrets are represented by their hashes, the remaining secrets such as auth tokens are not included.
+func keyConfigFingerprint(key Key) string {
+	conf := key.ToConfig()
+	fields := []string{
+		string(conf.Type),
+		strconv.Itoa(key.Index()),
+		strconv.Itoa(key.Weight()),
+		key.SigAlgo().String(),
+		key.HashAlgo().String(),
+		conf.ResourceID,
+		conf.DerivationPath,
+		string(conf.Curve),
+		conf.Location,
+		conf.Env,
+		conf.GcloudAccount,
+		conf.Endpoint,
+		conf.KeychainService,
+		conf.KeychainAccount,
+		conf.CredentialID,
+		strings.Join(conf.Command, " "),
+	}
+
+	// derived BIP44 keys include the private key, the mnemonic identifies the key regardless of derivation
+	switch {
+	case conf.Mnemonic != "":
+		mnemonic := sha256.Sum256([]byte(conf.Mnemonic))
+		fields = append(fields, hex.EncodeToString(mnemonic[:]))
+	case conf.PrivateKey != nil:
+		fields = append(fields, conf.PrivateKey.PublicKey().String())
+	}
+	if conf.Encrypted != nil {
+		ciphertext := sha256.Sum256(conf.Encrypted.Ciphertext)
+		fields = append(fields, hex.EncodeToString(ciphertext[:]))
+	}
+
+	digest := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(digest[:])
+}
+
+// DetectHashAlgo signs a test message with the key and returns the hash algorithm with which the
+// signature verifies against the public key registered on the network for the account at the key index.
+//
+// If the key signs with a different hash algorithm than the one registered on the network, the
+// detected hash algorithm is returned together with an error, since the network rejects such signatures.
+func DetectHashAlgo(ctx context.Context, key Key, account *Account, gw gateway.Gateway) (crypto.HashAlgorithm, error) {
+	onChain, err := gw.GetAccount(account.Address)
+	if err != nil {
+		return crypto.UnknownHashAlgorithm, fmt.Errorf("failed to get account %s: %w", account.Address, err)
+	}
+
+	index := key.Index()
+	if index < 0 || index >= len(onChain.Keys) {
+		return crypto.UnknownHashAlgorithm, fmt.Errorf(
+			"account %s only has %d keys on the network, key index %d does not exist",
+			account.Address, len(onChain.Keys), index,
+		)
+	}
+	onChainKey := onChain.Keys[index]
+
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return crypto.UnknownHashAlgorithm, err
+	}
+
+	message := []byte("flow hash algorithm detection")
+	signature, err := signer.Sign(message)
+	if err != nil {
+		return crypto.UnknownHashAlgorithm, err
+	}
+
+	for _, hashAlgo := range []crypto.HashAlgorithm{crypto.SHA2_256, crypto.SHA3_256} {
+		hasher, err := crypto.NewHasher(hashAlgo)
+		if err != nil {
+			return crypto.UnknownHashAlgorithm, err
+		}
+
+		valid, err := onChainKey.PublicKey.Verify(signature, message, hasher)
+		if err != nil || !valid {
+			continue
+		}
+
+		if hashAlgo != onChainKey.HashAlgo {
+			return hashAlgo, fmt.Errorf(
+				"key signs using %s but the key at index %d of account %s is registered with %s",
+				hashAlgo, index, account.Address, onChainKey.HashAlgo,
+			)
+		}
+
+		return hashAlgo, nil
+	}
+
+	return crypto.UnknownHashAlgorithm, fmt.Errorf(
+		"key does not match the key at index %d of account %s on the network",
+		index, account.Address,
+	)
+}
+
+// AlgoMismatch is a local key whose algorithms differ from the key registered on the network at the same index.
+type AlgoMismatch struct {
+	Index           int
+	SigAlgo         crypto.SignatureAlgorithm
+	HashAlgo        crypto.HashAlgorithm
+	NetworkSigAlgo  crypto.SignatureAlgorithm
+	NetworkHashAlgo crypto.HashAlgorithm
+}
+
+func (m AlgoMismatch) String() string {
+	return fmt.Sprintf(
+		"key index %d is configured with %s and %s but registered with %s and %s",
+		m.Index, m.SigAlgo, m.HashAlgo, m.NetworkSigAlgo, m.NetworkHashAlgo,
+	)
+}
+
+// AuditAlgorithms fetches the account from the network and reports the key indices used by the account,
+// including the role key indices, at which the configured algorithms differ from the registered ones.
+//
+// A key configured with a different hash algorithm signs successfully, but the network rejects the
+// signatures when the transaction is submitted, so the audit is useful right after deploying.
+func (a *Account) AuditAlgorithms(ctx context.Context, gw gateway.Gateway) ([]AlgoMismatch, error) {
+	if a.Key == nil {
+		return nil, fmt.Errorf("account %s has no key", a.Name)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	onChain, err := gw.GetAccount(a.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account %s: %w", a.Address, err)
+	}
+
+	indices := []int{a.Key.Index()}
+	for _, index := range a.RoleKeyIndices {
+		if !slices.Contains(indices, index) {
+			indices = append(indices, index)
+		}
+	}
+	slices.Sort(indices)
+
+	mismatches := make([]AlgoMismatch, 0)
+	for _, index := range indices {
+		if index < 0 || index >= len(onChain.Keys) {
+			return nil, fmt.Errorf(
+				"account %s only has %d keys on the network, key index %d does not exist",
+				a.Address, len(onChain.Keys), index,
+			)
+		}
+
+		onChainKey := onChain.Keys[index]
+		if onChainKey.SigAlgo != a.Key.SigAlgo() || onChainKey.HashAlgo != a.Key.HashAlgo() {
+			mismatches = append(mismatches, AlgoMismatch{
+				Index:           index,
+				SigAlgo:         a.Key.SigAlgo(),
+				HashAlgo:        a.Key.HashAlgo(),
+				NetworkSigAlgo:  onChainKey.SigAlgo,
+				NetworkHashAlgo: onChainKey.HashAlgo,
+			})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// CheckKeyIndices cross-checks the key indices of the accounts with the address of the account
+// fetched from the network and returns an error listing all the problems found.
+//
+// A problem is reported when a key index points to a nonexistent or revoked key, when the key
+// doesn't match the key registered at that index, or when the local key indices are not contiguous.
+func (a *Accounts) CheckKeyIndices(onChain *flow.Account) error {
+	problems := make([]string, 0)
+	indices := make([]int, 0)
+
+	for _, acc := range *a {
+		if acc.Address != onChain.Address || acc.Key == nil {
+			continue
+		}
+
+		index := acc.Key.Index()
+		if !slices.Contains(indices, index) {
+			indices = append(indices, index)
+		}
+
+		if index < 0 || index >= len(onChain.Keys) {
+			problems = append(problems, fmt.Sprintf(
+				"account %s uses key index %d but the account only has %d keys on the network",
+				acc.Name, index, len(onChain.Keys),
+			))
+			continue
+		}
+
+		onChainKey := onChain.Keys[index]
+		if onChainKey.Revoked {
+			problems = append(problems, fmt.Sprintf("account %s uses key index %d which is revoked", acc.Name, index))
+		}
+
+		// only compare keys held locally, loading other keys would reach remote services or prompt
+		if !isLocalKey(acc.Key) {
+			continue
+		}
+		if pkey, err := acc.Key.PrivateKey(); err == nil && !(*pkey).PublicKey().Equals(onChainKey.PublicKey) {
+			problems = append(problems, fmt.Sprintf(
+				"account %s key does not match the key at index %d on the network",
+				acc.Name, index,
+			))
+		}
+	}
+
+	slices.Sort(indices)
+	missing := make([]string, 0)
+	for i := 1; i < len(indices); i++ {
+		for gap := indices[i-1] + 1; gap < indices[i]; gap++ {
+			missing = append(missing, fmt.Sprintf("%d", gap))
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf(
+			"key indices for address %s are not contiguous, missing %s",
+			onChain.Address, strings.Join(missing, ", "),
+		))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("keys do not match the account on the network: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// RequiredSigningKeys selects the local keys needed to reach the full weight required to authorize the account.
+//
+// The weight of each local key is taken from the on-chain key with the same index, revoked keys are skipped.
+// Keys with higher weight are selected first to sign with as few keys as possible. An error is returned
+// with the missing weight if all the local keys together can't reach the full weight.
+func RequiredSigningKeys(onChainKeys []flow.AccountKey, localKeys []Key) ([]Key, error) {
+	weights := make(map[int]int)
+	for _, k := range onChainKeys {
+		if !k.Revoked {
+			weights[k.Index] = k.Weight
+		}
+	}
+
+	candidates := make([]Key, 0)
+	used := make(map[int]bool) // count every on-chain key once
+	for _, k := range localKeys {
+		if _, ok := weights[k.Index()]; ok && !used[k.Index()] {
+			candidates = append(candidates, k)
+			used[k.Index()] = true
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b Key) bool {
+		return weights[a.Index()] > weights[b.Index()]
+	})
+
+	selected := make([]Key, 0)
+	total := 0
+	for _, k := range candidates {
+		if total >= flow.AccountKeyWeightThreshold {
+			break
+		}
+		selected = append(selected, k)
+		total += weights[k.Index()]
+	}
+
+	if total < flow.AccountKeyWeightThreshold {
+		return nil, fmt.Errorf(
+			"local keys have a total weight of %d, missing %d to reach the required weight of %d",
+			total,
+			flow.AccountKeyWeightThreshold-total,
+			flow.AccountKeyWeightThreshold,
+		)
+	}
+
+	return selected, nil
+}
+
+// AuthKey is a key signing to authorize an account.
+type AuthKey struct {
+	// Account is the name of the local account holding the key.
+	Account string
+	Index   int
+	Weight  int
+}
+
+// AuthorizationPlan lists the keys signing to authorize an account in the order they sign.
+type AuthorizationPlan struct {
+	Keys []AuthKey
+	// Weight is the total weight of the keys.
+	Weight int
+}
+
+// Achievable returns whether the keys reach the weight required to authorize the account.
+func (p *AuthorizationPlan) Achievable() bool {
+	return p.Weight >= flow.AccountKeyWeightThreshold
+}
+
+// AuthorizationPlan returns the key the account authorizes with, which only achieves full
+// authorization if the key has the full weight.
+//
+// The weight is the one defined in the configuration, which should match the key weight on the network.
+func (a *Account) AuthorizationPlan() (*AuthorizationPlan, error) {
+	return authorizationPlan([]*Account{a})
+}
+
+// AuthorizationPlan returns the keys of the local accounts with the address that sign to authorize it.
+//
+// Accounts sharing an address hold different keys of the same network account, which sign together as
+// a multisig. Keys with higher weight sign first and no more keys are used once the full weight is reached.
+func (a *Accounts) AuthorizationPlan(address flow.Address) (*AuthorizationPlan, error) {
+	accounts := make([]*Account, 0)
+	for i := range *a {
+		if (*a)[i].Address == address {
+			accounts = append(accounts, &(*a)[i])
+		}
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no account with address %s found", address)
+	}
+
+	return authorizationPlan(accounts)
+}
+
+func authorizationPlan(accounts []*Account) (*AuthorizationPlan, error) {
+	candidates := make([]AuthKey, 0)
+	used := make(map[int]bool) // every key index signs once
+	for _, acc := range accounts {
+		if acc.Key == nil {
+			return nil, fmt.Errorf("account %s has no key", acc.Name)
+		}
+
+		weight := acc.Key.Weight()
+		if weight < 0 || weight > flow.AccountKeyWeightThreshold {
+			return nil, fmt.Errorf("invalid weight %d of the key for account %s", weight, acc.Name)
+		}
+
+		index := acc.RoleKeyIndex(SignRoleAuthorizer)
+		if used[index] {
+			continue
+		}
+		used[index] = true
+		candidates = append(candidates, AuthKey{Account: acc.Name, Index: index, Weight: weight})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b AuthKey) bool {
+		return a.Weight > b.Weight
+	})
+
+	plan := &AuthorizationPlan{Keys: make([]AuthKey, 0)}
+	for _, key := range candidates {
+		if plan.Achievable() {
+			break
+		}
+		plan.Keys = append(plan.Keys, key)
+		plan.Weight += key.Weight
+	}
+
+	return plan, nil
+}
+
+// isLocalKey reports whether the private key is held in the configuration or in a local file.
+func isLocalKey(key Key) bool {
+	switch key.(type) {
+	case *HexKey, *FileKey, *BIP44Key:
+		return true
+	}
+	return false
+}
+
+// PublicFingerprint returns a value identifying the key if it can be computed from the key metadata alone,
+// such as the resource ID of KMS keys, without reading key files, decrypting keys or reaching remote services.
+//
+// Keys referencing a key held elsewhere are identified by the reference, such as the remote signer key URL or
+// the keystore entry, while keys holding the private key, such as hex, BIP44 and file keys, have no public fingerprint.
+func PublicFingerprint(key Key) (string, bool) {
+	switch k := key.(type) {
+	case *KMSKey:
+		return k.ResourceKey().ResourceID(), true
+	case *RemoteKMSKey:
+		return k.resourceID, true
+	case *RemoteHTTPKey:
+		return k.keyURL(), true
+	case *KeystoreKey:
+		return fmt.Sprintf("%s#%s", k.location, k.id), true
+	case *KeychainKey:
+		return fmt.Sprintf("%s/%s", k.service, k.account), true
+	case *WebAuthnKey:
+		return k.credentialID, true
+	case *URLKey:
+		return redactedURL(k.location), true
+	case *CommandKey:
+		return k.commandName(), true
+	}
+	return "", false
+}
+
+// keyFingerprint returns a value identifying the key, using the public key if it can't be identified by the metadata.
+func keyFingerprint(key Key) (string, error) {
+	if fingerprint, ok := PublicFingerprint(key); ok {
+		return fingerprint, nil
+	}
+
+	publicKey, err := keyPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return publicKey.String(), nil
+}
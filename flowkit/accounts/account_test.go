@@ -19,10 +19,25 @@
 package accounts
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
 	"github.com/stretchr/testify/assert"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/config/json"
+	"github.com/onflow/flow-cli/flowkit/gateway/mocks"
+	"github.com/onflow/flow-cli/flowkit/tests"
 )
 
 func Test_Accounts(t *testing.T) {
@@ -125,4 +140,1216 @@ func Test_Accounts(t *testing.T) {
 		assert.EqualError(t, err, "could not find account with address 0000000000000001 in the configuration")
 	})
 
+	t.Run("Derivation indices", func(t *testing.T) {
+		const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+		bip44 := func(m string, path string) Key {
+			return &BIP44Key{baseKey: &baseKey{keyType: config.KeyTypeBip44}, mnemonic: m, derivationPath: path}
+		}
+
+		accs := Accounts{
+			Account{Name: "alice", Key: bip44(mnemonic, "m/44'/539'/0'/0/2")},
+			Account{Name: "bob", Key: bip44(mnemonic, "m/44'/539'/0'/0/0")},
+			Account{Name: "charlie", Key: bip44("other mnemonic", "m/44'/539'/0'/0/5")},
+			Account{Name: "dave", Key: NewFileKey("./dave.pkey", 0, config.DefaultSigAlgo, config.DefaultHashAlgo)},
+		}
+
+		// the gap left by a removed account is not reused
+		indices, next, undetermined, err := accs.DerivationIndices(mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint32{0, 2}, indices)
+		assert.Equal(t, uint32(3), next)
+		assert.Empty(t, undetermined)
+
+		accs = append(accs, Account{Name: "frank", Key: bip44(strings.ToUpper(mnemonic)+"  ", "m/44'/539'/0'/0/4")})
+		indices, next, _, err = accs.DerivationIndices("  " + mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint32{0, 2, 4}, indices)
+		assert.Equal(t, uint32(5), next)
+
+		indices, next, _, err = accs.DerivationIndices("unused mnemonic")
+		assert.NoError(t, err)
+		assert.Empty(t, indices)
+		assert.Equal(t, uint32(0), next)
+
+		accs = append(accs, Account{Name: "grace", Key: &BIP44Key{
+			baseKey:        &baseKey{keyType: config.KeyTypeBip44},
+			encrypted:      &config.EncryptedSecret{},
+			derivationPath: "m/44'/539'/0'/0/9",
+		}})
+		indices, _, undetermined, err = accs.DerivationIndices(mnemonic)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint32{0, 2, 4}, indices)
+		assert.Equal(t, []string{"grace"}, undetermined)
+
+		accs = append(accs, Account{Name: "eve", Key: bip44(mnemonic, "invalid")})
+		_, _, _, err = accs.DerivationIndices(mnemonic)
+		assert.EqualError(t, err, "invalid derivation path defined for account eve")
+	})
+
+}
+
+func Test_SignTransaction(t *testing.T) {
+	pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, make([]byte, crypto.MinSeedLength))
+	assert.NoError(t, err)
+	publicKey := pkey.PublicKey()
+
+	account := &Account{
+		Name:    "alice",
+		Address: flow.HexToAddress("0x01"),
+		Key:     NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey),
+	}
+
+	tx := flow.NewTransaction().
+		SetProposalKey(account.Address, 1, 0).
+		SetPayer(account.Address)
+
+	err = account.SignTransaction(context.Background(), tx, SignRoleProposer)
+	assert.NoError(t, err)
+	assert.Len(t, tx.PayloadSignatures, 1)
+	assert.Equal(t, 1, tx.PayloadSignatures[0].KeyIndex)
+
+	err = account.SignTransaction(context.Background(), tx, SignRolePayer)
+	assert.NoError(t, err)
+	assert.Len(t, tx.EnvelopeSignatures, 1)
+	assert.Equal(t, account.Address, tx.EnvelopeSignatures[0].Address)
+
+	valid, err := publicKey.Verify(
+		tx.EnvelopeSignatures[0].Signature,
+		append(flow.TransactionDomainTag[:], tx.EnvelopeMessage()...),
+		crypto.NewSHA3_256(),
+	)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	err = (&Account{Name: "bob"}).SignTransaction(context.Background(), tx, SignRolePayer)
+	assert.EqualError(t, err, "account bob is missing the key")
+}
+
+func Test_SignTransactionZeroWeight(t *testing.T) {
+	pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, make([]byte, crypto.MinSeedLength))
+	assert.NoError(t, err)
+
+	key, err := keyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeHex,
+		Index:      1,
+		Weight:     0,
+		WeightSet:  true,
+		SigAlgo:    crypto.ECDSA_P256,
+		HashAlgo:   crypto.SHA3_256,
+		PrivateKey: pkey,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, key.Weight())
+	assert.True(t, key.ToConfig().WeightSet)
+
+	account := &Account{
+		Name:    "alice",
+		Address: flow.HexToAddress("0x01"),
+		Key:     key,
+	}
+
+	tx := flow.NewTransaction().
+		SetProposalKey(account.Address, 1, 0).
+		SetPayer(account.Address)
+
+	err = account.SignTransaction(context.Background(), tx, SignRolePayer)
+	assert.EqualError(t, err, "account alice has no keys with nonzero weight")
+	assert.Empty(t, tx.EnvelopeSignatures)
+}
+
+func Test_SignedWeight(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	key := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+	key.weight = 400
+	account := &Account{
+		Name:           "alice",
+		Address:        flow.HexToAddress("0x01"),
+		Key:            key,
+		RoleKeyIndices: map[SignRole]int{SignRolePayer: 1},
+	}
+
+	tx := flow.NewTransaction().
+		SetProposalKey(account.Address, 0, 0).
+		SetPayer(account.Address).
+		AddAuthorizer(account.Address)
+	assert.Equal(t, 0, account.SignedWeight(tx, SignRoleAuthorizer))
+	assert.Equal(t, 0, account.SignedWeight(tx, SignRolePayer))
+
+	err := account.SignTransaction(context.Background(), tx, SignRoleAuthorizer)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, account.SignedWeight(tx, SignRoleAuthorizer))
+	assert.Equal(t, 0, account.SignedWeight(tx, SignRolePayer))
+
+	err = account.SignTransaction(context.Background(), tx, SignRolePayer)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, account.SignedWeight(tx, SignRoleAuthorizer))
+	assert.Equal(t, 400, account.SignedWeight(tx, SignRolePayer))
+
+	other := &Account{Name: "bob", Address: flow.HexToAddress("0x02"), Key: key}
+	assert.Equal(t, 0, other.SignedWeight(tx, SignRoleAuthorizer))
+
+	t.Run("Proposer index", func(t *testing.T) {
+		account.RoleKeyIndices = map[SignRole]int{SignRoleProposer: 1}
+		tx := flow.NewTransaction().
+			SetProposalKey(account.Address, 1, 0).
+			SetPayer(flow.HexToAddress("0x02")).
+			AddAuthorizer(account.Address)
+
+		err := account.SignTransaction(context.Background(), tx, SignRoleProposer)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, account.SignedWeight(tx, SignRoleProposer))
+		assert.Equal(t, 0, account.SignedWeight(tx, SignRoleAuthorizer))
+
+		err = account.SignTransaction(context.Background(), tx, SignRoleAuthorizer)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, account.SignedWeight(tx, SignRoleAuthorizer))
+	})
+}
+
+func Test_NewAccountFromAddressAndKey(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	account, err := NewAccountFromAddressAndKey(flow.HexToAddress("0x01"), pkey, crypto.SHA2_256)
+	assert.NoError(t, err)
+	assert.Equal(t, flow.HexToAddress("0x01"), account.Address)
+	assert.Equal(t, crypto.SHA2_256, account.Key.HashAlgo())
+	assert.Equal(t, crypto.ECDSA_P256, account.Key.SigAlgo())
+
+	signer, err := account.Key.Signer(context.Background())
+	assert.NoError(t, err)
+	sig, err := signer.Sign([]byte("message"))
+	assert.NoError(t, err)
+	valid, err := pkey.PublicKey().Verify(sig, []byte("message"), crypto.NewSHA2_256())
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = NewAccountFromAddressAndKey(flow.HexToAddress("0x01"), pkey, crypto.SHA2_384)
+	assert.EqualError(t, err, "invalid hash algorithm SHA2_384 for signature algorithm ECDSA_P256")
+}
+
+func Test_AuthorizationPlan(t *testing.T) {
+	newAccount := func(name string, index int, weight int) Account {
+		key := &HexKey{baseKey: &baseKey{keyType: config.KeyTypeHex, index: index, weight: weight}}
+		return Account{Name: name, Address: flow.HexToAddress("0x01"), Key: key}
+	}
+
+	t.Run("Single account", func(t *testing.T) {
+		account := newAccount("alice", 1, 0)
+		plan, err := account.AuthorizationPlan()
+		assert.NoError(t, err)
+		assert.Equal(t, []AuthKey{{Account: "alice", Index: 1, Weight: 1000}}, plan.Keys)
+		assert.True(t, plan.Achievable())
+
+		account = newAccount("alice", 1, 400)
+		plan, err = account.AuthorizationPlan()
+		assert.NoError(t, err)
+		assert.Equal(t, 400, plan.Weight)
+		assert.False(t, plan.Achievable())
+	})
+
+	t.Run("Multisig", func(t *testing.T) {
+		accs := Accounts{
+			newAccount("first", 0, 300),
+			newAccount("second", 1, 600),
+			newAccount("second-duplicate", 1, 600),
+			newAccount("third", 2, 500),
+			{Name: "other", Address: flow.HexToAddress("0x02"), Key: &HexKey{baseKey: &baseKey{}}},
+		}
+
+		plan, err := accs.AuthorizationPlan(flow.HexToAddress("0x01"))
+		assert.NoError(t, err)
+		assert.Equal(t, []AuthKey{
+			{Account: "second", Index: 1, Weight: 600},
+			{Account: "third", Index: 2, Weight: 500},
+		}, plan.Keys)
+		assert.Equal(t, 1100, plan.Weight)
+		assert.True(t, plan.Achievable())
+
+		_, err = accs.AuthorizationPlan(flow.HexToAddress("0x03"))
+		assert.EqualError(t, err, "no account with address 0000000000000003 found")
+	})
+
+	t.Run("Not achievable", func(t *testing.T) {
+		accs := Accounts{newAccount("first", 0, 300), newAccount("second", 1, 200)}
+		plan, err := accs.AuthorizationPlan(flow.HexToAddress("0x01"))
+		assert.NoError(t, err)
+		assert.Len(t, plan.Keys, 2)
+		assert.Equal(t, 500, plan.Weight)
+		assert.False(t, plan.Achievable())
+	})
+}
+
+func Test_AccountAddressFormatting(t *testing.T) {
+	account := &Account{Name: "alice", Address: flow.HexToAddress("f8d6e0586b0a20c7")}
+	assert.Equal(t, "0xf8d6e0586b0a20c7", account.FormattedAddress())
+	assert.Equal(t, "0xf8d6...20c7", account.ShortAddress())
+
+	account = &Account{Name: "bob", Address: flow.HexToAddress("01")}
+	assert.Equal(t, "0x0000000000000001", account.FormattedAddress())
+	assert.Equal(t, "0x0000...0001", account.ShortAddress())
+
+	account = &Account{Name: "charlie"}
+	assert.Equal(t, "", account.FormattedAddress())
+	assert.Equal(t, "", account.ShortAddress())
+}
+
+func Test_DeferredAddressAccount(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	account := NewDeferredAddressAccount("alice", NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey))
+	assert.False(t, account.HasAddress())
+
+	tx := flow.NewTransaction().SetPayer(flow.HexToAddress("0x01"))
+	err := account.SignTransaction(context.Background(), tx, SignRolePayer)
+	assert.EqualError(t, err, "address of account alice is not set, set it with SetAddress once the account is created")
+
+	assert.EqualError(t, account.SetAddress(flow.EmptyAddress), "can not set an empty address for account alice")
+	assert.NoError(t, account.SetAddress(flow.HexToAddress("0x01")))
+	assert.True(t, account.HasAddress())
+	assert.NoError(t, account.SignTransaction(context.Background(), tx, SignRolePayer))
+
+	assert.NoError(t, account.SetAddress(flow.HexToAddress("0x01")))
+	assert.EqualError(t, account.SetAddress(flow.HexToAddress("0x02")), "account alice already has the address 0000000000000001")
+}
+
+func Test_CanAuthorizeAlone(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	account := &Account{Name: "alice", Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)}
+	alone, err := account.CanAuthorizeAlone()
+	assert.NoError(t, err)
+	assert.True(t, alone)
+
+	key := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+	key.weight = 500
+	account.Key = key
+	alone, err = account.CanAuthorizeAlone()
+	assert.NoError(t, err)
+	assert.False(t, alone)
+
+	_, err = (&Account{Name: "bob"}).CanAuthorizeAlone()
+	assert.EqualError(t, err, "account bob has no key")
+}
+
+func Test_GenerateEmulatorAccount(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		account, keys, err := GenerateEmulatorAccount(EmulatorAccountOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, config.DefaultEmulator.ServiceAccount, account.Name)
+		assert.Equal(t, flow.ServiceAddress(flow.Emulator), account.Address)
+		assert.Len(t, keys, 1)
+		assert.Equal(t, keys[0], account.Key)
+		assert.Equal(t, config.DefaultSigAlgo, account.Key.SigAlgo())
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		opts := EmulatorAccountOptions{
+			Name:     "alice",
+			Address:  flow.HexToAddress("0x01"),
+			Keys:     3,
+			Weights:  []int{500, 500},
+			SigAlgo:  crypto.ECDSA_secp256k1,
+			HashAlgo: crypto.SHA2_256,
+			Seed:     bytes.Repeat([]byte{1}, crypto.MinSeedLength),
+		}
+		account, keys, err := GenerateEmulatorAccount(opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", account.Name)
+		assert.Len(t, keys, 3)
+
+		for i, key := range keys {
+			assert.Equal(t, i, key.Index())
+			assert.Equal(t, crypto.ECDSA_secp256k1, key.SigAlgo())
+			assert.Equal(t, crypto.SHA2_256, key.HashAlgo())
+		}
+		assert.Equal(t, 500, keys[1].Weight())
+		assert.Equal(t, flow.AccountKeyWeightThreshold, keys[2].Weight())
+
+		_, again, err := GenerateEmulatorAccount(opts)
+		assert.NoError(t, err)
+		assert.Equal(t, keys, again)
+		assert.NotEqual(t, keys[0].ToConfig().PrivateKey, keys[1].ToConfig().PrivateKey)
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		_, _, err := GenerateEmulatorAccount(EmulatorAccountOptions{Weights: []int{1, 1}})
+		assert.EqualError(t, err, "2 weights provided for 1 keys")
+
+		_, _, err = GenerateEmulatorAccount(EmulatorAccountOptions{Weights: []int{1001}})
+		assert.EqualError(t, err, "invalid weight 1001 of key 0")
+
+		_, _, err = GenerateEmulatorAccount(EmulatorAccountOptions{Seed: []byte{1}})
+		assert.EqualError(t, err, "seed must be at least 32 bytes long")
+	})
+}
+
+func Test_DetectDuplicateKeys(t *testing.T) {
+	newKey := func(seed byte) Key {
+		pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, bytes.Repeat([]byte{seed}, crypto.MinSeedLength))
+		assert.NoError(t, err)
+		return NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+	}
+
+	accs := Accounts{
+		Account{Name: "alice", Address: flow.HexToAddress("0x01"), Key: newKey(1)},
+		Account{Name: "alice-testnet", Address: flow.HexToAddress("0x01"), Key: newKey(1)},
+		Account{Name: "bob", Address: flow.HexToAddress("0x02"), Key: newKey(2)},
+	}
+	assert.NoError(t, accs.DetectDuplicateKeys())
+
+	accs = append(accs, Account{Name: "charlie", Address: flow.HexToAddress("0x03"), Key: newKey(2)})
+	assert.EqualError(t, accs.DetectDuplicateKeys(), "accounts use duplicate keys: bob and charlie")
+
+	t.Run("Skip keys without a public key", func(t *testing.T) {
+		t.Setenv(PassphraseEnv, "")
+		encrypted, err := EncryptSecret([]byte("dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"), "passphrase")
+		assert.NoError(t, err)
+		encryptedKey, err := hexKeyFromConfig(config.AccountKey{Type: config.KeyTypeHex, Encrypted: encrypted})
+		assert.NoError(t, err)
+
+		accs := Accounts{
+			Account{Name: "alice", Address: flow.HexToAddress("0x01"), Key: newKey(1)},
+			Account{Name: "bob", Address: flow.HexToAddress("0x02"), Key: encryptedKey},
+			Account{Name: "charlie", Address: flow.HexToAddress("0x03"), Key: encryptedKey},
+		}
+		assert.NoError(t, accs.DetectDuplicateKeys())
+	})
+}
+
+func Test_MergeAccounts(t *testing.T) {
+	newKey := func(seed byte, index int) Key {
+		pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, bytes.Repeat([]byte{seed}, crypto.MinSeedLength))
+		assert.NoError(t, err)
+		return NewHexKeyFromPrivateKey(index, crypto.SHA3_256, pkey)
+	}
+	address := flow.HexToAddress("0x01")
+
+	t.Run("Merge", func(t *testing.T) {
+		base := &Account{
+			Name:           "alice",
+			Address:        address,
+			Labels:         map[string]string{"owner": "alice", "env": "base"},
+			RoleKeyIndices: map[SignRole]int{SignRolePayer: 1},
+		}
+		override := &Account{
+			Address:        address,
+			Key:            newKey(1, 0),
+			Labels:         map[string]string{"env": "override"},
+			RoleKeyIndices: map[SignRole]int{SignRoleProposer: 2},
+		}
+
+		merged, err := MergeAccounts(base, override)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", merged.Name)
+		assert.Equal(t, override.Key, merged.Key)
+		assert.Equal(t, map[string]string{"owner": "alice", "env": "override"}, merged.Labels)
+		assert.Equal(t, map[SignRole]int{SignRolePayer: 1, SignRoleProposer: 2}, merged.RoleKeyIndices)
+
+		merged, err = MergeAccounts(&Account{Name: "alice", Address: address, Key: newKey(1, 0)}, override)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, merged.Key.Index())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		_, err := MergeAccounts(&Account{Name: "alice", Address: address}, &Account{Name: "bob", Address: flow.HexToAddress("0x02")})
+		assert.EqualError(t, err, "can not merge accounts alice and bob with different addresses")
+
+		_, err = MergeAccounts(&Account{Name: "alice", Address: address, Key: newKey(1, 0)}, &Account{Address: address, Key: newKey(2, 0)})
+		assert.EqualError(t, err, "conflicting keys at index 0 of account alice")
+
+		_, err = MergeAccounts(&Account{Name: "alice", Address: address, Key: newKey(1, 0)}, &Account{Address: address, Key: newKey(2, 1)})
+		assert.EqualError(t, err, "can not merge keys at indices 0 and 1 of account alice, an account holds a single key")
+	})
+
+	t.Run("Keys without a public key", func(t *testing.T) {
+		t.Setenv(PassphraseEnv, "")
+		encryptedKey := func(passphrase string) Key {
+			encrypted, err := EncryptSecret([]byte("dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"), passphrase)
+			assert.NoError(t, err)
+			key, err := hexKeyFromConfig(config.AccountKey{Type: config.KeyTypeHex, Encrypted: encrypted})
+			assert.NoError(t, err)
+			return key
+		}
+
+		key := encryptedKey("passphrase")
+		merged, err := MergeAccounts(&Account{Name: "alice", Address: address, Key: key}, &Account{Address: address, Key: key})
+		assert.NoError(t, err)
+		assert.Equal(t, key, merged.Key)
+
+		_, err = MergeAccounts(&Account{Name: "alice", Address: address, Key: key}, &Account{Address: address, Key: encryptedKey("other")})
+		assert.EqualError(t, err, "conflicting keys at index 0 of account alice")
+	})
+}
+
+func Test_AccountsFingerprint(t *testing.T) {
+	newKey := func(seed byte) Key {
+		pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, bytes.Repeat([]byte{seed}, crypto.MinSeedLength))
+		assert.NoError(t, err)
+		return NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+	}
+
+	alice := &Account{Name: "alice", Address: flow.HexToAddress("0x01"), Key: newKey(1)}
+	bob := &Account{Name: "bob", Address: flow.HexToAddress("0x02"), Key: newKey(2)}
+
+	fingerprint := AccountsFingerprint([]*Account{alice, bob})
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, AccountsFingerprint([]*Account{bob, alice}))
+
+	changed := &Account{Name: "bob", Address: flow.HexToAddress("0x02"), Key: newKey(3)}
+	assert.NotEqual(t, fingerprint, AccountsFingerprint([]*Account{alice, changed}))
+
+	renamed := &Account{Name: "charlie", Address: bob.Address, Key: bob.Key}
+	assert.NotEqual(t, fingerprint, AccountsFingerprint([]*Account{alice, renamed}))
+
+	t.Run("Keys are not loaded", func(t *testing.T) {
+		SetSecretPrompt(func(_ string) (string, error) {
+			t.Fatal("secret prompt must not be called")
+			return "", nil
+		})
+		defer SetSecretPrompt(nil)
+
+		encrypted, err := EncryptSecret([]byte("dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"), "passphrase")
+		assert.NoError(t, err)
+		encryptedKey, err := hexKeyFromConfig(config.AccountKey{Type: config.KeyTypeHex, Encrypted: encrypted})
+		assert.NoError(t, err)
+		remoteKey := func(keyID string) Key {
+			key, err := remoteHTTPKeyFromConfig(config.AccountKey{Type: config.KeyTypeRemoteHTTP, Endpoint: "http://127.0.0.1:1", ResourceID: keyID})
+			assert.NoError(t, err)
+			return key
+		}
+
+		accounts := []*Account{
+			{Name: "alice", Address: flow.HexToAddress("0x01"), Key: encryptedKey},
+			{Name: "bob", Address: flow.HexToAddress("0x02"), Key: remoteKey("bob")},
+		}
+		fingerprint := AccountsFingerprint(accounts)
+
+		t.Setenv(PassphraseEnv, "passphrase")
+		assert.Equal(t, fingerprint, AccountsFingerprint(accounts))
+
+		accounts[1].Key = remoteKey("charlie")
+		assert.NotEqual(t, fingerprint, AccountsFingerprint(accounts))
+	})
+
+	t.Run("BIP44 derivation", func(t *testing.T) {
+		key, err := bip44KeyFromConfig(config.AccountKey{
+			Type:     config.KeyTypeBip44,
+			Mnemonic: "version field tornado move level pretty inject stereo ten catalog salon swallow",
+		})
+		assert.NoError(t, err)
+		accounts := []*Account{{Name: "alice", Address: flow.HexToAddress("0x01"), Key: key}}
+		fingerprint := AccountsFingerprint(accounts)
+
+		_, err = key.PrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, fingerprint, AccountsFingerprint(accounts))
+	})
+}
+
+func Test_AccountValidate(t *testing.T) {
+	pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, make([]byte, crypto.MinSeedLength))
+	assert.NoError(t, err)
+
+	account := &Account{
+		Name:    "alice",
+		Address: flow.ServiceAddress(flow.Emulator),
+		Key:     NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+	}
+	assert.NoError(t, account.Validate())
+	assert.NoError(t, account.ValidateForChain(flow.Emulator))
+	assert.EqualError(
+		t,
+		account.ValidateForChain(flow.Mainnet),
+		"account alice is invalid: address f8d6e0586b0a20c7 is not valid on flow-mainnet",
+	)
+
+	account = &Account{
+		Name: "bob",
+		Key:  NewFileKey("./missing.pkey", -1, crypto.ECDSA_P256, crypto.SHA3_256),
+	}
+	err = account.Validate()
+	assert.ErrorContains(t, err, "account bob is invalid: missing address; invalid key index -1")
+
+	account = &Account{Name: "charlie", Address: flow.HexToAddress("0x01")}
+	assert.EqualError(t, account.Validate(), "account charlie is invalid: missing key")
+}
+
+func Test_AccountsValidate(t *testing.T) {
+	pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, make([]byte, crypto.MinSeedLength))
+	assert.NoError(t, err)
+
+	address := flow.ServiceAddress(flow.Emulator)
+	accs := Accounts{
+		{Name: "alice", Address: address, Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)},
+		{Name: "alice-backup", Address: address, Key: NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey)},
+		{Name: "bob", Address: flow.HexToAddress("0x01"), Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)},
+	}
+	assert.NoError(t, accs.Validate())
+	assert.EqualError(
+		t,
+		accs.ValidateForChain(flow.Emulator),
+		"accounts are invalid: account bob is invalid: address 0000000000000001 is not valid on flow-emulator",
+	)
+
+	// all the problems are reported at once
+	accs = append(accs,
+		Account{Name: "alice-copy", Address: address, Key: NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey)},
+		Account{Name: "charlie", Address: flow.HexToAddress("0x02")},
+	)
+	assert.EqualError(
+		t,
+		accs.Validate(),
+		"accounts are invalid: "+
+			"accounts alice-backup and alice-copy use the same key index 1 of address f8d6e0586b0a20c7; "+
+			"account charlie is invalid: missing key",
+	)
+}
+
+func Test_PredictAccountAddress(t *testing.T) {
+	assert.Equal(t, flow.ServiceAddress(flow.Emulator), PredictAccountAddress(flow.Emulator, 1))
+	assert.Equal(t, "ee82856bf20e2aa6", PredictAccountAddress(flow.Emulator, 2).String())
+	assert.Equal(t, "01cf0e2f2f715450", PredictAccountAddress(flow.Emulator, 5).String())
+	assert.Equal(t, flow.ServiceAddress(flow.Testnet), PredictAccountAddress(flow.Testnet, 1))
+}
+
+func Test_GenerateVanityEmulatorAccount(t *testing.T) {
+	account, keys, index, err := GenerateVanityEmulatorAccount("^f3", 0, EmulatorAccountOptions{Name: "vanity"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), index)
+	assert.Equal(t, "f3fcd2c1a78f5eee", account.Address.String())
+	assert.Equal(t, "vanity", account.Name)
+	assert.Len(t, keys, 1)
+
+	// the keys are derived from the address so the same account is generated again
+	again, _, _, err := GenerateVanityEmulatorAccount("^f3", 0, EmulatorAccountOptions{Name: "vanity"})
+	assert.NoError(t, err)
+	same, err := SameKey(account.Key, again.Key)
+	assert.NoError(t, err)
+	assert.True(t, same)
+
+	_, _, _, err = GenerateVanityEmulatorAccount("^f3", 5, EmulatorAccountOptions{})
+	assert.EqualError(t, err, "no emulator address matching ^f3 found in 5 attempts")
+
+	_, _, _, err = GenerateVanityEmulatorAccount("[", 0, EmulatorAccountOptions{})
+	assert.ErrorContains(t, err, "invalid vanity address pattern [")
+}
+
+func Test_NewEmulatorAccountFromMnemonic(t *testing.T) {
+	const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+
+	acc, err := NewEmulatorAccountFromMnemonic(mnemonic, "", crypto.ECDSA_P256, crypto.SHA3_256)
+	assert.NoError(t, err)
+	assert.Equal(t, flow.ServiceAddress(flow.Emulator), acc.Address)
+	assert.Equal(t, config.DefaultEmulator.ServiceAccount, acc.Name)
+	assert.Equal(t, "m/44'/539'/0'/0/0", acc.Key.ToConfig().DerivationPath)
+
+	pkey, err := acc.Key.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be",
+		(*pkey).PublicKey().String(),
+	)
+
+	_, err = NewEmulatorAccountFromMnemonic("invalid mnemonic", "", crypto.ECDSA_P256, crypto.SHA3_256)
+	assert.ErrorContains(t, err, "failed to derive emulator service key")
+}
+
+func Test_CheckKeyIndices(t *testing.T) {
+	pkey1, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	pkey2, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+	assert.NoError(t, err)
+
+	address := flow.HexToAddress("01cf0e2f2f715450")
+	onChain := &flow.Account{
+		Address: address,
+		Keys: []*flow.AccountKey{
+			{Index: 0, PublicKey: pkey1.PublicKey()},
+			{Index: 1, PublicKey: pkey2.PublicKey(), Revoked: true},
+			{Index: 2, PublicKey: pkey2.PublicKey()},
+		},
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		accounts := Accounts{
+			{Name: "alice", Address: address, Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey1)},
+			{Name: "bob", Address: flow.HexToAddress("179b6b1cb6755e31"), Key: NewHexKeyFromPrivateKey(5, crypto.SHA3_256, pkey1)},
+		}
+		assert.NoError(t, accounts.CheckKeyIndices(onChain))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		accounts := Accounts{
+			{Name: "alice", Address: address, Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey2)},
+			{Name: "alice-revoked", Address: address, Key: NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey2)},
+			{Name: "alice-missing", Address: address, Key: NewHexKeyFromPrivateKey(4, crypto.SHA3_256, pkey2)},
+		}
+		assert.EqualError(t, accounts.CheckKeyIndices(onChain), "keys do not match the account on the network: "+
+			"account alice key does not match the key at index 0 on the network; "+
+			"account alice-revoked uses key index 1 which is revoked; "+
+			"account alice-missing uses key index 4 but the account only has 3 keys on the network; "+
+			"key indices for address 01cf0e2f2f715450 are not contiguous, missing 2, 3",
+		)
+	})
+
+	t.Run("Remote keys not loaded", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			_, _ = w.Write([]byte(pkey2.String()))
+		}))
+		defer server.Close()
+
+		client := urlKeyClient
+		urlKeyClient = server.Client()
+		defer func() { urlKeyClient = client }()
+
+		key, err := keyFromConfig(config.AccountKey{
+			Type:     config.KeyTypeURL,
+			Index:    0,
+			SigAlgo:  crypto.ECDSA_P256,
+			HashAlgo: crypto.SHA3_256,
+			Location: server.URL + "/key",
+		})
+		assert.NoError(t, err)
+
+		accounts := Accounts{{Name: "alice", Address: address, Key: key}}
+		assert.NoError(t, accounts.CheckKeyIndices(onChain))
+		assert.Equal(t, 0, requests)
+	})
+}
+
+func Test_FromConfigLenient(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	conf := &config.Config{
+		Accounts: config.Accounts{
+			{Name: "alice", Address: flow.HexToAddress("01"), Key: config.NewDefaultAccountKey(pkey)},
+			{Name: "bob", Address: flow.HexToAddress("02"), Key: config.AccountKey{Type: "ledger"}},
+			{Name: "charlie", Address: flow.HexToAddress("03"), Key: config.NewDefaultAccountKey(pkey)},
+			{Name: "dave", Address: flow.HexToAddress("04"), Key: config.AccountKey{Type: config.KeyTypeKeychain}},
+		},
+	}
+
+	_, err = FromConfig(conf)
+	assert.Error(t, err)
+
+	accounts, errs := FromConfigLenient(conf)
+	assert.Equal(t, []string{"alice", "charlie"}, accounts.Names())
+	assert.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], `invalid account bob: invalid key type: "ledger"`)
+	assert.EqualError(t, errs[1], "invalid account dave: missing service or account for the keychain key")
+}
+
+func Test_MarshalConfigJSON(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	alice := Account{
+		Name:    "alice",
+		Address: flow.HexToAddress("f8d6e0586b0a20c7"),
+		Key:     NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+	}
+
+	raw, err := alice.MarshalConfigJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"alice": {
+			"address": "f8d6e0586b0a20c7",
+			"key": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+		}
+	}`, string(raw))
+
+	bob := Account{
+		Name:    "bob",
+		Address: flow.HexToAddress("01cf0e2f2f715450"),
+		Key:     NewHexKeyFromPrivateKey(2, crypto.SHA2_256, pkey),
+	}
+
+	raw, err = bob.MarshalConfigJSON()
+	assert.NoError(t, err)
+
+	conf, err := json.NewParser().Deserialize([]byte(fmt.Sprintf(`{"accounts": %s}`, raw)))
+	assert.NoError(t, err)
+
+	accounts, err := FromConfig(conf)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, bob.Name, accounts[0].Name)
+	assert.Equal(t, bob.Address, accounts[0].Address)
+	same, err := SameKey(bob.Key, accounts[0].Key)
+	assert.NoError(t, err)
+	assert.True(t, same)
+}
+
+func Test_DefaultAlgorithmsConfigJSON(t *testing.T) {
+	const keyHex = "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+
+	SetDefaultAlgorithms(crypto.ECDSA_secp256k1, crypto.SHA2_256)
+	defer SetDefaultAlgorithms(config.DefaultSigAlgo, config.DefaultHashAlgo)
+
+	conf, err := json.NewParser().Deserialize([]byte(fmt.Sprintf(`{
+		"accounts": {
+			"alice": {
+				"address": "01cf0e2f2f715450",
+				"key": { "type": "hex", "index": 1, "privateKey": "%[1]s" }
+			},
+			"bob": {
+				"address": "179b6b1cb6755e31",
+				"key": {
+					"type": "hex",
+					"index": 1,
+					"signatureAlgorithm": "ECDSA_P256",
+					"hashAlgorithm": "SHA3_256",
+					"privateKey": "%[1]s"
+				}
+			},
+			"charlie": { "address": "f3fcd2c1a78f5eee", "key": "%[1]s" }
+		}
+	}`, keyHex)))
+	assert.NoError(t, err)
+
+	accs, err := FromConfig(conf)
+	assert.NoError(t, err)
+
+	// the simple format always uses P256 and SHA3_256 independent of the project defaults
+	for name, sigAlgo := range map[string]crypto.SignatureAlgorithm{
+		"alice":   crypto.ECDSA_secp256k1,
+		"bob":     crypto.ECDSA_P256,
+		"charlie": crypto.ECDSA_P256,
+	} {
+		acc, err := accs.ByName(name)
+		assert.NoError(t, err)
+		assert.Equal(t, sigAlgo, acc.Key.SigAlgo())
+
+		pkey, err := acc.Key.PrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, sigAlgo, (*pkey).Algorithm())
+	}
+
+	alice, err := accs.ByName("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.SHA2_256, alice.Key.HashAlgo())
+
+	// the project defaults are not saved, so keys using them are saved with explicit algorithms
+	aliceRaw, err := alice.MarshalConfigJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(aliceRaw), `"signatureAlgorithm": "ECDSA_secp256k1"`)
+	assert.Contains(t, string(aliceRaw), `"hashAlgorithm": "SHA2_256"`)
+
+	bob, err := accs.ByName("bob")
+	assert.NoError(t, err)
+	raw, err := bob.MarshalConfigJSON()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), "signatureAlgorithm")
+	assert.NotContains(t, string(raw), "hashAlgorithm")
+
+	charlie, err := accs.ByName("charlie")
+	assert.NoError(t, err)
+	raw, err = charlie.MarshalConfigJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), fmt.Sprintf(`"key": "%s"`, keyHex))
+
+	// a process using other defaults loads the saved key as the same key
+	SetDefaultAlgorithms(config.DefaultSigAlgo, config.DefaultHashAlgo)
+	conf, err = json.NewParser().Deserialize([]byte(fmt.Sprintf(`{"accounts": %s}`, aliceRaw)))
+	assert.NoError(t, err)
+	reloaded, err := FromConfig(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.ECDSA_secp256k1, reloaded[0].Key.SigAlgo())
+	assert.Equal(t, crypto.SHA2_256, reloaded[0].Key.HashAlgo())
+}
+
+func Test_StrictAlgorithmsConfigJSON(t *testing.T) {
+	SetStrictAlgorithms(true)
+	defer SetStrictAlgorithms(false)
+
+	conf, err := json.NewParser().Deserialize([]byte(`{
+		"accounts": {
+			"emulator-account": {
+				"address": "f8d6e0586b0a20c7",
+				"key": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			},
+			"advanced": {
+				"address": "01cf0e2f2f715450",
+				"key": { "type": "hex", "privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47" }
+			}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	accs, err := FromConfig(conf)
+	assert.NoError(t, err)
+	for _, acc := range accs {
+		assert.NoError(t, acc.Key.Validate())
+		_, err := acc.Key.Signer(context.Background())
+		assert.NoError(t, err)
+	}
+}
+
+func Test_AuditAlgorithms(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	address := flow.HexToAddress("01cf0e2f2f715450")
+	gw := &mocks.Gateway{}
+	gw.On(mocks.GetAccountFunc, address).Return(&flow.Account{
+		Address: address,
+		Keys: []*flow.AccountKey{
+			{Index: 0, PublicKey: pkey.PublicKey(), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256, Weight: 1000},
+			{Index: 1, PublicKey: pkey.PublicKey(), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA2_256, Weight: 1000},
+		},
+	}, nil)
+
+	t.Run("Matching", func(t *testing.T) {
+		acc := &Account{Name: "test", Address: address, Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)}
+		mismatches, err := acc.AuditAlgorithms(context.Background(), gw)
+		assert.NoError(t, err)
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("Mismatched role key", func(t *testing.T) {
+		acc := &Account{
+			Name:           "test",
+			Address:        address,
+			Key:            NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+			RoleKeyIndices: map[SignRole]int{SignRoleProposer: 1},
+		}
+		mismatches, err := acc.AuditAlgorithms(context.Background(), gw)
+		assert.NoError(t, err)
+		assert.Equal(t, []AlgoMismatch{{
+			Index:           1,
+			SigAlgo:         crypto.ECDSA_P256,
+			HashAlgo:        crypto.SHA3_256,
+			NetworkSigAlgo:  crypto.ECDSA_P256,
+			NetworkHashAlgo: crypto.SHA2_256,
+		}}, mismatches)
+		assert.Equal(
+			t,
+			"key index 1 is configured with ECDSA_P256 and SHA3_256 but registered with ECDSA_P256 and SHA2_256",
+			mismatches[0].String(),
+		)
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		acc := &Account{Name: "test", Address: address, Key: NewHexKeyFromPrivateKey(2, crypto.SHA3_256, pkey)}
+		_, err := acc.AuditAlgorithms(context.Background(), gw)
+		assert.EqualError(t, err, "account 01cf0e2f2f715450 only has 2 keys on the network, key index 2 does not exist")
+	})
+}
+
+func Test_DetectHashAlgo(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	other, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+	assert.NoError(t, err)
+
+	address := flow.HexToAddress("01cf0e2f2f715450")
+	gw := &mocks.Gateway{}
+	gw.On(mocks.GetAccountFunc, address).Return(&flow.Account{
+		Address: address,
+		Keys: []*flow.AccountKey{
+			{Index: 0, PublicKey: pkey.PublicKey(), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256, Weight: 1000},
+			{Index: 1, PublicKey: other.PublicKey(), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA2_256, Weight: 1000},
+		},
+	}, nil)
+
+	tests := []struct {
+		name     string
+		key      Key
+		expected crypto.HashAlgorithm
+		err      string
+	}{
+		{
+			name:     "Matching",
+			key:      NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+			expected: crypto.SHA3_256,
+		},
+		{
+			name:     "Matching SHA2",
+			key:      NewHexKeyFromPrivateKey(1, crypto.SHA2_256, other),
+			expected: crypto.SHA2_256,
+		},
+		{
+			name:     "Mismatched",
+			key:      NewHexKeyFromPrivateKey(0, crypto.SHA2_256, pkey),
+			expected: crypto.SHA2_256,
+			err:      "key signs using SHA2_256 but the key at index 0 of account 01cf0e2f2f715450 is registered with SHA3_256",
+		},
+		{
+			name:     "Wrong Key",
+			key:      NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey),
+			expected: crypto.UnknownHashAlgorithm,
+			err:      "key does not match the key at index 1 of account 01cf0e2f2f715450 on the network",
+		},
+		{
+			name:     "Missing Index",
+			key:      NewHexKeyFromPrivateKey(2, crypto.SHA3_256, pkey),
+			expected: crypto.UnknownHashAlgorithm,
+			err:      "account 01cf0e2f2f715450 only has 2 keys on the network, key index 2 does not exist",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			account := &Account{Name: "alice", Address: address, Key: test.key}
+			hashAlgo, err := DetectHashAlgo(context.Background(), test.key, account, gw)
+			assert.Equal(t, test.expected, hashAlgo)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func Test_AccountLabels(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	conf := &config.Config{
+		Accounts: config.Accounts{{
+			Name:    "alice",
+			Address: flow.HexToAddress("01"),
+			Key:     config.NewDefaultAccountKey(pkey),
+			Labels:  map[string]string{"team": "payments"},
+		}},
+	}
+
+	accounts, err := FromConfig(conf)
+	assert.NoError(t, err)
+	alice := &accounts[0]
+
+	team, ok := alice.Label("team")
+	assert.True(t, ok)
+	assert.Equal(t, "payments", team)
+	_, ok = alice.Label("env")
+	assert.False(t, ok)
+
+	alice.SetLabel("env", "staging")
+	assert.Equal(t, map[string]string{"team": "payments"}, conf.Accounts[0].Labels)
+	assert.Equal(t, map[string]string{"team": "payments", "env": "staging"}, ToConfig(accounts)[0].Labels)
+
+	bob := &Account{Name: "bob"}
+	bob.SetLabel("team", "growth")
+	assert.Equal(t, map[string]string{"team": "growth"}, bob.Labels)
+
+	raw, err := alice.MarshalConfigJSON()
+	assert.NoError(t, err)
+	restored, err := json.NewParser().Deserialize([]byte(fmt.Sprintf(`{"accounts": %s}`, raw)))
+	assert.NoError(t, err)
+	assert.Equal(t, alice.Labels, restored.Accounts[0].Labels)
+}
+
+func Test_RoleSigner(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	publicKey := pkey.PublicKey()
+
+	conf := &config.Config{
+		Accounts: config.Accounts{{
+			Name:           "alice",
+			Address:        flow.HexToAddress("01"),
+			Key:            config.NewDefaultAccountKey(pkey),
+			RoleKeyIndices: map[string]int{"proposer": 3},
+		}},
+	}
+
+	accounts, err := FromConfig(conf)
+	assert.NoError(t, err)
+	alice := &accounts[0]
+	assert.Equal(t, conf.Accounts[0].RoleKeyIndices, ToConfig(accounts)[0].RoleKeyIndices)
+
+	signer, index, err := alice.RoleSigner(context.Background(), SignRoleProposer)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, index)
+	assert.True(t, signer.PublicKey().Equals(publicKey))
+
+	_, index, err = alice.RoleSigner(context.Background(), SignRolePayer)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, index)
+
+	t.Run("Sign", func(t *testing.T) {
+		tx := flow.NewTransaction().
+			SetProposalKey(alice.Address, 3, 0).
+			SetPayer(alice.Address)
+
+		assert.NoError(t, alice.SignTransaction(context.Background(), tx, SignRoleProposer))
+		assert.NoError(t, alice.SignTransaction(context.Background(), tx, SignRolePayer))
+		assert.Len(t, tx.PayloadSignatures, 1)
+		assert.Equal(t, 3, tx.PayloadSignatures[0].KeyIndex)
+		assert.Len(t, tx.EnvelopeSignatures, 1)
+		assert.Equal(t, 0, tx.EnvelopeSignatures[0].KeyIndex)
+	})
+
+	t.Run("Fail invalid role", func(t *testing.T) {
+		conf.Accounts[0].RoleKeyIndices = map[string]int{"signer": 1}
+		_, err := FromConfig(conf)
+		assert.EqualError(t, err, "invalid transaction role signer, must be one of proposer, authorizer, payer")
+	})
+
+	t.Run("Fail invalid index", func(t *testing.T) {
+		alice.RoleKeyIndices[SignRolePayer] = -1
+		assert.ErrorContains(t, alice.Validate(), "invalid payer key index -1")
+	})
+}
+
+func Test_FindSigner(t *testing.T) {
+	pkey1, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	pkey2, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+	assert.NoError(t, err)
+
+	address := flow.HexToAddress("01cf0e2f2f715450")
+	accounts := Accounts{
+		{Name: "alice", Address: address, Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey1)},
+		{Name: "alice-backup", Address: address, Key: NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey2)},
+	}
+
+	signer, err := accounts.FindSigner(context.Background(), address, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, pkey2.PublicKey().String(), signer.PublicKey().String())
+
+	_, err = accounts.FindSigner(context.Background(), address, 2)
+	assert.EqualError(t, err, "could not find account with address 01cf0e2f2f715450 and key index 2 in the configuration")
+}
+
+func Test_RequiredSigningKeys(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	onChainKeys := []flow.AccountKey{
+		{Index: 0, Weight: 500},
+		{Index: 1, Weight: 1000, Revoked: true},
+		{Index: 2, Weight: 300},
+		{Index: 3, Weight: 700},
+	}
+	key := func(index int) Key {
+		return NewHexKeyFromPrivateKey(index, crypto.SHA3_256, pkey)
+	}
+
+	t.Run("Highest weight first", func(t *testing.T) {
+		keys, err := RequiredSigningKeys(onChainKeys, []Key{key(0), key(2), key(3)})
+		assert.NoError(t, err)
+		assert.Len(t, keys, 2)
+		assert.Equal(t, 3, keys[0].Index())
+		assert.Equal(t, 0, keys[1].Index())
+	})
+
+	t.Run("Revoked and unknown keys are skipped", func(t *testing.T) {
+		_, err := RequiredSigningKeys(onChainKeys, []Key{key(1), key(2), key(2), key(5)})
+		assert.EqualError(t, err, "local keys have a total weight of 300, missing 700 to reach the required weight of 1000")
+	})
+}
+
+func Test_MetadataOnlyTraversal(t *testing.T) {
+	accesses := make([]string, 0)
+	record := func(access string) { accesses = append(accesses, access) }
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record(fmt.Sprintf("request %s", r.URL.Path))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := urlKeyClient
+	urlKeyClient = server.Client()
+	defer func() { urlKeyClient = client }()
+
+	originalPublicKey, originalAlgorithm, originalCredentials, originalKeychain :=
+		kmsPublicKey, kmsKeyAlgorithm, hasApplicationDefaultCredentials, keychainSecret
+	defer func() {
+		kmsPublicKey, kmsKeyAlgorithm, hasApplicationDefaultCredentials, keychainSecret =
+			originalPublicKey, originalAlgorithm, originalCredentials, originalKeychain
+	}()
+	kmsPublicKey = func(_ context.Context, _ cloudkms.Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+		record("kms public key")
+		return nil, crypto.UnknownHashAlgorithm, fmt.Errorf("not available")
+	}
+	kmsKeyAlgorithm = func(_ context.Context, _ cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+		record("kms algorithm")
+		return kmspb.CryptoKeyVersion_CRYPTO_KEY_VERSION_ALGORITHM_UNSPECIFIED, fmt.Errorf("not available")
+	}
+	hasApplicationDefaultCredentials = func() bool {
+		record("kms credentials")
+		return false
+	}
+	keychainSecret = func(_ string, _ string) (string, error) {
+		record("keychain")
+		return "", fmt.Errorf("not available")
+	}
+	originalCommand := commandOutput
+	defer func() { commandOutput = originalCommand }()
+	commandOutput = func(_ context.Context, _ []string) ([]byte, error) {
+		record("command")
+		return nil, fmt.Errorf("not available")
+	}
+	SetSecretPrompt(func(prompt string) (string, error) {
+		record("prompt")
+		return "", fmt.Errorf("not available")
+	})
+	defer SetSecretPrompt(nil)
+
+	encrypted := &config.EncryptedSecret{Ciphertext: []byte{1}, Nonce: []byte{2}, Salt: []byte{3}, N: 1, R: 1, P: 1}
+	keys := []config.AccountKey{
+		{Type: config.KeyTypeHex, Encrypted: encrypted},
+		{Type: config.KeyTypeBip44, Index: 1, Encrypted: encrypted},
+		{Type: config.KeyTypeFile, Index: 2, Location: filepath.Join(t.TempDir(), "missing.pkey")},
+		{Type: config.KeyTypeGoogleKMS, ResourceID: "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"},
+		{Type: config.KeyTypeKMS, ResourceID: "arn:aws:kms:us-east-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+		{Type: config.KeyTypeRemoteHTTP, Endpoint: server.URL, ResourceID: "key", InsecureSkipVerify: true},
+		{Type: config.KeyTypeKeychain, KeychainService: "flow", KeychainAccount: "alice"},
+		{Type: config.KeyTypeWebAuthn, CredentialID: "credential"},
+		{Type: config.KeyTypeURL, Location: server.URL + "/key"},
+		{Type: config.KeyTypeCommand, Command: []string{"op", "read", "op://flow/key"}},
+		{Type: config.KeyTypeKeystore, Location: filepath.Join(t.TempDir(), "keys.db"), ResourceID: "alice"},
+	}
+
+	keystore := keys[10].Location
+	fingerprints := []string{
+		"",
+		"",
+		"",
+		keys[3].ResourceID,
+		keys[4].ResourceID,
+		server.URL + "/keys/key",
+		"flow/alice",
+		"credential",
+		server.URL + "/key",
+		"op read op://flow/key",
+		keystore + "#alice",
+	}
+
+	conf := &config.Config{}
+	for i, key := range keys {
+		conf.Accounts = append(conf.Accounts, config.Account{
+			Name:    fmt.Sprintf("account-%d", i),
+			Address: flow.HexToAddress("0x01"),
+			Key:     key,
+		})
+	}
+
+	accs, err := FromConfig(conf)
+	assert.NoError(t, err)
+	assert.Len(t, accs, len(keys))
+
+	for i, acc := range accs {
+		assert.Equal(t, keys[i].Type, acc.Key.Type())
+		assert.Equal(t, keys[i].Index, acc.Key.Index())
+		assert.Equal(t, flow.AccountKeyWeightThreshold, acc.Key.Weight())
+		assert.Equal(t, crypto.ECDSA_P256, acc.Key.SigAlgo())
+		assert.Equal(t, crypto.SHA3_256, acc.Key.HashAlgo())
+		assert.Equal(t, keys[i].Type, acc.Key.ToConfig().Type)
+		assert.NotEmpty(t, fmt.Sprint(acc.Key))
+
+		fingerprint, ok := PublicFingerprint(acc.Key)
+		assert.Equal(t, fingerprints[i] != "", ok)
+		assert.Equal(t, fingerprints[i], fingerprint)
+	}
+
+	assert.Empty(t, accesses)
 }
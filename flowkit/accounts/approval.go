@@ -0,0 +1,170 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+var _ crypto.Signer = &ApprovalGatedSigner{}
+
+// ApprovalGatedSigner is a signer that only signs after an approval webhook approves the signature.
+//
+// Before signing, the signer posts {"publicKey": "<hex>", "digest": "<hex>", "message": "<hex>"} to the webhook,
+// where the digest is the SHA3-256 hash of the message identifying it, and waits for the response
+// {"approved": <bool>, "reason": "<text>"}. The webhook can block until a person decides,
+// the signer gives up after the timeout, or waits until the context is cancelled if the timeout is zero.
+type ApprovalGatedSigner struct {
+	ctx     context.Context
+	signer  crypto.Signer
+	webhook string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewApprovalGatedSigner wraps the signer so every signature must be approved by the webhook within the timeout,
+// a timeout of zero or less means no timeout.
+func NewApprovalGatedSigner(signer crypto.Signer, webhook string, timeout time.Duration) *ApprovalGatedSigner {
+	return &ApprovalGatedSigner{
+		ctx:     context.Background(),
+		signer:  signer,
+		webhook: webhook,
+		timeout: timeout,
+		client:  http.DefaultClient,
+	}
+}
+
+func (s *ApprovalGatedSigner) Sign(message []byte) ([]byte, error) {
+	err := s.approve(message)
+	if err != nil {
+		return nil, err
+	}
+	return s.signer.Sign(message)
+}
+
+func (s *ApprovalGatedSigner) PublicKey() crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+// approve requests the approval of the message and returns an error unless it was approved.
+func (s *ApprovalGatedSigner) approve(message []byte) error {
+	body, err := json.Marshal(map[string]string{
+		"publicKey": s.signer.PublicKey().String(),
+		"digest":    hex.EncodeToString(crypto.NewSHA3_256().ComputeHash(message)),
+		"message":   hex.EncodeToString(message),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.ctx, context.CancelFunc(func() {})
+	if s.timeout > 0 {
+		ctx, cancel = context.WithTimeout(s.ctx, s.timeout)
+	}
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if id := CorrelationID(s.ctx); id != "" {
+		req.Header.Set(CorrelationIDHeader, id)
+	}
+
+	res, err := s.client.Do(req)
+	if s.timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("approval webhook did not respond within %s", s.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("approval webhook request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	if err != nil {
+		return fmt.Errorf("failed to read approval webhook response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("approval webhook returned status %d: %s", res.StatusCode, bytes.TrimSpace(data))
+	}
+
+	var decision struct {
+		Approved bool   `json:"approved"`
+		Reason   string `json:"reason"`
+	}
+	err = json.Unmarshal(data, &decision)
+	if err != nil {
+		return fmt.Errorf("invalid approval webhook response: %w", err)
+	}
+
+	if !decision.Approved {
+		if decision.Reason == "" {
+			return fmt.Errorf("signing was denied by the approval webhook")
+		}
+		return fmt.Errorf("signing was denied by the approval webhook: %s", decision.Reason)
+	}
+
+	return nil
+}
+
+var _ Key = &ApprovalGatedKey{}
+
+// ApprovalGatedKey wraps a key so its signers only sign after the approval webhook approves the signature.
+type ApprovalGatedKey struct {
+	Key
+	webhook string
+	timeout time.Duration
+}
+
+// NewApprovalGatedKey wraps the key so every signature must be approved by the webhook within the timeout,
+// a timeout of zero or less means no timeout.
+func NewApprovalGatedKey(key Key, webhook string, timeout time.Duration) *ApprovalGatedKey {
+	return &ApprovalGatedKey{
+		Key:     key,
+		webhook: webhook,
+		timeout: timeout,
+	}
+}
+
+// Signer returns the gated signer, the context is used for the approval requests.
+func (k *ApprovalGatedKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	signer, err := k.Key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gated := NewApprovalGatedSigner(signer, k.webhook, k.timeout)
+	gated.ctx = ctx
+	return gated, nil
+}
+
+func (k *ApprovalGatedKey) String() string {
+	return fmt.Sprintf("ApprovalGatedKey{%s, webhook:%s}", k.Key, k.webhook)
+}
@@ -0,0 +1,99 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_ApprovalGatedKey(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	message := []byte("message")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, hex.EncodeToString(message), req["message"])
+		assert.Equal(t, hex.EncodeToString(crypto.NewSHA3_256().ComputeHash(message)), req["digest"])
+		assert.Equal(t, pkey.PublicKey().String(), req["publicKey"])
+
+		switch r.URL.Path {
+		case "/approve":
+			_, _ = w.Write([]byte(`{"approved": true}`))
+		case "/deny":
+			_, _ = w.Write([]byte(`{"approved": false, "reason": "not during the freeze"}`))
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"approved": true}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("unavailable"))
+		}
+	}))
+	defer server.Close()
+
+	signWithin := func(path string, timeout time.Duration) ([]byte, error) {
+		key := NewApprovalGatedKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), server.URL+path, timeout)
+		signer, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+		return signer.Sign(message)
+	}
+	sign := func(path string) ([]byte, error) {
+		return signWithin(path, 100*time.Millisecond)
+	}
+
+	t.Run("Approved", func(t *testing.T) {
+		sig, err := sign("/approve")
+		assert.NoError(t, err)
+		valid, err := pkey.PublicKey().Verify(sig, message, crypto.NewSHA3_256())
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		_, err := sign("/deny")
+		assert.EqualError(t, err, "signing was denied by the approval webhook: not during the freeze")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		_, err := sign("/slow")
+		assert.EqualError(t, err, "approval webhook did not respond within 100ms")
+	})
+
+	t.Run("No timeout", func(t *testing.T) {
+		_, err := signWithin("/slow", 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fail status", func(t *testing.T) {
+		_, err := sign("/invalid")
+		assert.EqualError(t, err, "approval webhook returned status 500: unavailable")
+	})
+}
@@ -0,0 +1,161 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// PublicKeyCache stores public keys derived from mnemonics in an encrypted file, so the keys
+// don't have to be derived again after the process restarts.
+//
+// Entries are keyed by the mnemonic fingerprint, the signature algorithm and the derivation path,
+// the mnemonic and the private keys are never stored.
+type PublicKeyCache struct {
+	path       string
+	passphrase string
+	mu         sync.Mutex
+	entries    map[string]string
+}
+
+// NewPublicKeyCache creates a cache stored at the path and encrypted with the passphrase.
+//
+// The file is created on the first derived key, an unreadable file is treated as empty and replaced.
+func NewPublicKeyCache(path string, passphrase string) *PublicKeyCache {
+	return &PublicKeyCache{
+		path:       path,
+		passphrase: passphrase,
+	}
+}
+
+var publicKeyCache *PublicKeyCache
+
+// SetPublicKeyCache sets the cache used by BIP44 keys for the derived public keys,
+// setting it to nil disables caching.
+func SetPublicKeyCache(cache *PublicKeyCache) {
+	publicKeyCache = cache
+}
+
+// mnemonicFingerprint identifies the mnemonic without revealing it.
+func mnemonicFingerprint(mnemonic string) string {
+	hash := sha256.Sum256([]byte("flow-public-key-cache:" + mnemonic))
+	return hex.EncodeToString(hash[:8])
+}
+
+func cacheEntryKey(mnemonic string, sigAlgo crypto.SignatureAlgorithm, derivationPath string) string {
+	return fmt.Sprintf("%s/%s/%s", mnemonicFingerprint(mnemonic), sigAlgo, derivationPath)
+}
+
+// get returns the cached public key, missing or invalid entries return nil.
+func (c *PublicKeyCache) get(mnemonic string, sigAlgo crypto.SignatureAlgorithm, derivationPath string) crypto.PublicKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	encoded, ok := c.entries[cacheEntryKey(mnemonic, sigAlgo, derivationPath)]
+	if !ok {
+		return nil
+	}
+
+	publicKey, err := crypto.DecodePublicKeyHex(sigAlgo, encoded)
+	if err != nil {
+		return nil
+	}
+
+	return publicKey
+}
+
+// put adds the public key to the cache and writes the cache file.
+func (c *PublicKeyCache) put(
+	mnemonic string,
+	sigAlgo crypto.SignatureAlgorithm,
+	derivationPath string,
+	publicKey crypto.PublicKey,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+
+	c.entries[cacheEntryKey(mnemonic, sigAlgo, derivationPath)] = hex.EncodeToString(publicKey.Encode())
+
+	raw, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := EncryptSecret(raw, c.passphrase)
+	if err != nil {
+		return err
+	}
+
+	raw, err = json.Marshal(encrypted)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create public key cache directory: %w", err)
+	}
+
+	err = os.WriteFile(c.path, raw, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write public key cache: %w", err)
+	}
+
+	return nil
+}
+
+// load reads the cache file once, the cache must be locked.
+func (c *PublicKeyCache) load() {
+	if c.entries != nil {
+		return
+	}
+	c.entries = make(map[string]string)
+
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	var encrypted config.EncryptedSecret
+	if json.Unmarshal(raw, &encrypted) != nil {
+		return
+	}
+
+	decrypted, err := DecryptSecret(&encrypted, c.passphrase)
+	if err != nil {
+		return
+	}
+
+	entries := make(map[string]string)
+	if json.Unmarshal(decrypted, &entries) == nil {
+		c.entries = entries
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+func Test_PublicKeyCache(t *testing.T) {
+	const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+	path := filepath.Join(t.TempDir(), "cache", "keys.json")
+	conf := config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        crypto.ECDSA_P256,
+		HashAlgo:       crypto.SHA3_256,
+		Mnemonic:       mnemonic,
+		DerivationPath: "m/44'/539'/0'/0/0",
+	}
+	defer SetPublicKeyCache(nil)
+
+	SetPublicKeyCache(NewPublicKeyCache(path, "secret"))
+	key, err := bip44KeyFromConfig(conf)
+	assert.NoError(t, err)
+	assert.NoError(t, key.Validate())
+	derived, err := key.ToFlowAccountKey()
+	assert.NoError(t, err)
+
+	raw, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(raw), "version"))
+	assert.False(t, strings.Contains(string(raw), mnemonicFingerprint(mnemonic)))
+
+	t.Run("Restart", func(t *testing.T) {
+		SetPublicKeyCache(NewPublicKeyCache(path, "secret"))
+		key, err := bip44KeyFromConfig(conf)
+		assert.NoError(t, err)
+		assert.NoError(t, key.Validate())
+		assert.Nil(t, key.(*BIP44Key).privateKey)
+
+		cached, err := key.ToFlowAccountKey()
+		assert.NoError(t, err)
+		assert.Equal(t, derived.PublicKey, cached.PublicKey)
+		assert.Nil(t, key.(*BIP44Key).privateKey)
+
+		pkey, err := key.PrivateKey()
+		assert.NoError(t, err)
+		assert.True(t, (*pkey).PublicKey().Equals(derived.PublicKey))
+	})
+
+	t.Run("Other Path", func(t *testing.T) {
+		SetPublicKeyCache(NewPublicKeyCache(path, "secret"))
+		other := conf
+		other.DerivationPath = "m/44'/539'/0'/0/1"
+		key, err := bip44KeyFromConfig(other)
+		assert.NoError(t, err)
+		assert.NoError(t, key.Validate())
+		assert.NotNil(t, key.(*BIP44Key).privateKey)
+	})
+
+	t.Run("Wrong Passphrase", func(t *testing.T) {
+		SetPublicKeyCache(NewPublicKeyCache(path, "wrong"))
+		key, err := bip44KeyFromConfig(conf)
+		assert.NoError(t, err)
+		assert.NoError(t, key.Validate())
+		assert.NotNil(t, key.(*BIP44Key).privateKey)
+	})
+
+	t.Run("Invalid Mnemonic", func(t *testing.T) {
+		SetPublicKeyCache(NewPublicKeyCache(path, "secret"))
+		invalid := conf
+		invalid.Mnemonic = "invalid"
+		key, err := bip44KeyFromConfig(invalid)
+		assert.NoError(t, err)
+		assert.Error(t, key.Validate())
+	})
+}
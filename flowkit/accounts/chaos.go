@@ -0,0 +1,132 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// ChaosError is the error of the failures injected by the chaos signer.
+type ChaosError struct {
+	transient bool
+}
+
+func (e *ChaosError) Error() string {
+	if e.transient {
+		return "injected transient signing failure"
+	}
+	return "injected permanent signing failure"
+}
+
+// Temporary returns whether the failure is transient, in which case retrying the signing can succeed.
+func (e *ChaosError) Temporary() bool {
+	return e.transient
+}
+
+// ChaosOptions configures the failures injected by the chaos signer.
+type ChaosOptions struct {
+	// FailureRate is the fraction of sign calls that fail, between 0 and 1.
+	FailureRate float64
+	// TransientRate is the fraction of the failures that are transient, the rest are permanent.
+	TransientRate float64
+	// Seed makes the injected failures reproducible.
+	Seed int64
+}
+
+var _ crypto.Signer = &ChaosSigner{}
+
+// ChaosSigner is a signer that fails a fraction of the sign calls with a ChaosError.
+//
+// It is meant for testing how retries and batch signing handle intermittent signer failures.
+type ChaosSigner struct {
+	signer  crypto.Signer
+	options ChaosOptions
+	mu      sync.Mutex
+	rand    *rand.Rand
+}
+
+// NewChaosSigner wraps the signer so it fails sign calls as configured by the options.
+func NewChaosSigner(signer crypto.Signer, options ChaosOptions) *ChaosSigner {
+	return &ChaosSigner{
+		signer:  signer,
+		options: options,
+		rand:    rand.New(rand.NewSource(options.Seed)),
+	}
+}
+
+func (s *ChaosSigner) Sign(message []byte) ([]byte, error) {
+	s.mu.Lock() // the random source is not safe for concurrent use
+	fail := s.rand.Float64() < s.options.FailureRate
+	transient := s.rand.Float64() < s.options.TransientRate
+	s.mu.Unlock()
+
+	if fail {
+		return nil, &ChaosError{transient: transient}
+	}
+	return s.signer.Sign(message)
+}
+
+func (s *ChaosSigner) PublicKey() crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+var _ Key = &ChaosKey{}
+
+// ChaosKey wraps a key so all of its signers share a chaos signer failing sign calls as configured.
+type ChaosKey struct {
+	Key
+	options ChaosOptions
+	mu      sync.Mutex
+	signer  *ChaosSigner
+}
+
+// NewChaosKey wraps the key so its signers fail sign calls as configured by the options.
+func NewChaosKey(key Key, options ChaosOptions) *ChaosKey {
+	return &ChaosKey{
+		Key:     key,
+		options: options,
+	}
+}
+
+// Signer returns the chaos signer, the same signer is returned on every call so the failures are reproducible.
+func (k *ChaosKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.signer != nil {
+		return k.signer, nil
+	}
+
+	signer, err := k.Key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k.signer = NewChaosSigner(signer, k.options)
+	return k.signer, nil
+}
+
+func (k *ChaosKey) String() string {
+	return fmt.Sprintf("ChaosKey{%s, failureRate:%g}", k.Key, k.options.FailureRate)
+}
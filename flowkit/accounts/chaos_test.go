@@ -0,0 +1,78 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_ChaosSigner(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	signer, err := crypto.NewInMemorySigner(pkey, crypto.SHA3_256)
+	assert.NoError(t, err)
+
+	count := func(chaos crypto.Signer) (ok int, transient int, permanent int) {
+		for i := 0; i < 1000; i++ {
+			_, err := chaos.Sign([]byte("message"))
+			var chaosErr *ChaosError
+			switch {
+			case err == nil:
+				ok++
+			case errors.As(err, &chaosErr) && chaosErr.Temporary():
+				transient++
+			default:
+				permanent++
+			}
+		}
+		return ok, transient, permanent
+	}
+
+	t.Run("Failure rates", func(t *testing.T) {
+		ok, transient, permanent := count(NewChaosSigner(signer, ChaosOptions{FailureRate: 0.3, TransientRate: 0.5, Seed: 1}))
+		assert.InDelta(t, 700, ok, 60)
+		assert.InDelta(t, 150, transient, 40)
+		assert.InDelta(t, 150, permanent, 40)
+
+		// the same seed injects the same failures
+		okAgain, transientAgain, permanentAgain := count(NewChaosSigner(signer, ChaosOptions{FailureRate: 0.3, TransientRate: 0.5, Seed: 1}))
+		assert.Equal(t, []int{ok, transient, permanent}, []int{okAgain, transientAgain, permanentAgain})
+	})
+
+	t.Run("No failures", func(t *testing.T) {
+		ok, _, _ := count(NewChaosSigner(signer, ChaosOptions{}))
+		assert.Equal(t, 1000, ok)
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		key := NewChaosKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), ChaosOptions{FailureRate: 1})
+		chaos, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, pkey.PublicKey().String(), chaos.PublicKey().String())
+
+		_, err = chaos.Sign([]byte("message"))
+		assert.EqualError(t, err, "injected permanent signing failure")
+	})
+}
@@ -0,0 +1,177 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+var _ Key = &CommandKey{}
+
+// CommandKey is a key with the private key printed by a command, such as a secret manager CLI
+// like `op read` or `vault kv get`.
+//
+// The command is run without a shell on first use and must print the hex encoded private key to
+// the standard output, the key is cached so the command runs only once, even if signers are created
+// concurrently. The command output is never included in errors since it can contain the key.
+type CommandKey struct {
+	*baseKey
+	mu         sync.Mutex
+	command    []string
+	privateKey crypto.PrivateKey
+}
+
+func commandKeyFromConfig(key config.AccountKey) (*CommandKey, error) {
+	if len(key.Command) == 0 || key.Command[0] == "" {
+		return nil, fmt.Errorf("missing command printing the key")
+	}
+
+	return &CommandKey{
+		baseKey: baseKeyFromConfig(key),
+		command: append([]string(nil), key.Command...),
+	}, nil
+}
+
+func (k *CommandKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := k.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, k)
+	}
+
+	key, err := k.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemorySigner(key, k.HashAlgo())
+}
+
+func (k *CommandKey) PrivateKey() (*crypto.PrivateKey, error) {
+	key, err := k.load(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// load runs the command and decodes the key from its output, the key is cached after the first load.
+func (k *CommandKey) load(ctx context.Context) (crypto.PrivateKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.privateKey != nil {
+		return k.privateKey, nil
+	}
+
+	output, err := commandOutput(ctx, k.command)
+	if err != nil {
+		return nil, fmt.Errorf("key command %s failed: %w", k.commandName(), err)
+	}
+
+	pkey, err := config.DecodePrivateKeyHex(k.SigAlgo(), strings.TrimPrefix(strings.TrimSpace(string(output)), "0x"))
+	if err != nil { // the decoding error is not wrapped since it can include parts of the output
+		return nil, fmt.Errorf("key command %s did not print a valid %s private key", k.commandName(), k.SigAlgo())
+	}
+
+	k.privateKey = pkey
+	return pkey, nil
+}
+
+func (k *CommandKey) Validate() error {
+	return k.ValidateCtx(context.Background())
+}
+
+// ValidateCtx runs the command and checks it prints a valid key, cancelling the context stops the command.
+func (k *CommandKey) ValidateCtx(ctx context.Context) error {
+	if err := k.checkAlgorithms(); err != nil {
+		return err
+	}
+
+	_, err := k.load(ctx)
+	return err
+}
+
+// Prepare runs the command and caches the key.
+func (k *CommandKey) Prepare(ctx context.Context) error {
+	return k.ValidateCtx(ctx)
+}
+
+// ReadyToSign checks the command was already run or the program of the command is available.
+func (k *CommandKey) ReadyToSign(_ context.Context) (bool, error) {
+	k.mu.Lock()
+	loaded := k.privateKey != nil
+	k.mu.Unlock()
+
+	if signerProvider != nil || loaded {
+		return true, nil
+	}
+
+	_, err := exec.LookPath(k.command[0])
+	if err != nil {
+		return false, fmt.Errorf("key command %s is not available: %w", k.command[0], err)
+	}
+	return true, nil
+}
+
+func (k *CommandKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(k)
+}
+
+func (k *CommandKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(k)
+}
+
+func (k *CommandKey) ToConfig() config.AccountKey {
+	return config.AccountKey{
+		Type:      k.keyType,
+		Index:     k.index,
+		Weight:    k.weight,
+		WeightSet: k.weightSet,
+		SigAlgo:   k.sigAlgo,
+		HashAlgo:  k.hashAlgo,
+		Command:   append([]string(nil), k.command...),
+	}
+}
+
+func (k *CommandKey) String() string {
+	return fmt.Sprintf("CommandKey{%s, command:%s}", k.fields(), k.commandName())
+}
+
+func (k *CommandKey) commandName() string {
+	return strings.Join(k.command, " ")
+}
+
+// commandOutput runs the command and returns its standard output, the command must exit with status 0.
+var commandOutput = func(ctx context.Context, command []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	return cmd.Output() // the exit error doesn't include the output
+}
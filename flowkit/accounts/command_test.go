@@ -0,0 +1,144 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_CommandKey(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands are not available on windows")
+	}
+
+	const keyHex = "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, keyHex)
+
+	t.Run("Sign", func(t *testing.T) {
+		t.Setenv("COMMAND_KEY", "0x"+keyHex)
+		conf := config.AccountKey{
+			Type:     config.KeyTypeCommand,
+			SigAlgo:  crypto.ECDSA_P256,
+			HashAlgo: crypto.SHA3_256,
+			Command:  []string{"printenv", "COMMAND_KEY"},
+		}
+		key, err := keyFromConfig(conf)
+		assert.NoError(t, err)
+		assert.Equal(t, conf, key.ToConfig())
+
+		ready, err := key.ReadyToSign(context.Background())
+		assert.True(t, ready)
+		assert.NoError(t, err)
+
+		signer, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, pkey.PublicKey().String(), signer.PublicKey().String())
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		runs := 0
+		original := commandOutput
+		defer func() { commandOutput = original }()
+		commandOutput = func(_ context.Context, _ []string) ([]byte, error) {
+			runs++
+			return []byte(keyHex + "\n"), nil
+		}
+
+		key, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeCommand, Command: []string{"op", "read", "op://flow/key"}})
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err := key.PrivateKey()
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("Concurrent load", func(t *testing.T) {
+		runs := 0
+		original := commandOutput
+		defer func() { commandOutput = original }()
+		commandOutput = func(_ context.Context, _ []string) ([]byte, error) {
+			runs++
+			return []byte(keyHex + "\n"), nil
+		}
+
+		key, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeCommand, Command: []string{"op", "read", "op://flow/key"}})
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, err := key.Signer(context.Background())
+				assert.NoError(t, err)
+			}()
+			go func() {
+				defer wg.Done()
+				_, _ = key.ReadyToSign(context.Background())
+			}()
+		}
+		wg.Wait()
+
+		// the command ran once for all the signers
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("Fail exit status", func(t *testing.T) {
+		key, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeCommand, Command: []string{"false"}})
+		assert.NoError(t, err)
+
+		err = key.Validate()
+		assert.EqualError(t, err, "key command false failed: exit status 1")
+	})
+
+	t.Run("Fail invalid key redacted", func(t *testing.T) {
+		t.Setenv("COMMAND_KEY", "secret-but-not-a-key")
+		key, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeCommand, Command: []string{"printenv", "COMMAND_KEY"}})
+		assert.NoError(t, err)
+
+		_, err = key.Signer(context.Background())
+		assert.EqualError(t, err, "key command printenv COMMAND_KEY did not print a valid ECDSA_P256 private key")
+		assert.NotContains(t, err.Error(), "secret")
+	})
+
+	t.Run("Fail missing program", func(t *testing.T) {
+		key, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeCommand, Command: []string{"flow-missing-secret-cli"}})
+		assert.NoError(t, err)
+
+		ready, err := key.ReadyToSign(context.Background())
+		assert.False(t, ready)
+		assert.ErrorContains(t, err, "key command flow-missing-secret-cli is not available")
+	})
+
+	t.Run("Fail missing command", func(t *testing.T) {
+		_, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeCommand})
+		assert.EqualError(t, err, "missing command printing the key")
+	})
+}
@@ -0,0 +1,156 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// CompositeSelection is the strategy a composite account uses to select the account for an operation.
+type CompositeSelection int
+
+const (
+	// SelectRoundRobin selects the accounts in turn.
+	SelectRoundRobin CompositeSelection = iota
+	// SelectAvailable selects the accounts in turn, skipping the accounts whose key is not ready
+	// to sign or doesn't have the weight to sign for the account alone.
+	SelectAvailable
+)
+
+// CompositeAccount groups several accounts used interchangeably, such as funded accounts
+// spreading the load of sending transactions, and selects one of them for each operation.
+//
+// It is safe for concurrent use.
+type CompositeAccount struct {
+	Name      string
+	accounts  []*Account
+	selection CompositeSelection
+	mu        sync.Mutex
+	next      int
+}
+
+// NewCompositeAccount creates a composite account of the accounts selected using the strategy.
+func NewCompositeAccount(name string, accounts []*Account, selection CompositeSelection) (*CompositeAccount, error) {
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("composite account %s has no accounts", name)
+	}
+
+	addresses := make(map[flow.Address]bool)
+	for _, acc := range accounts {
+		if acc == nil || acc.Key == nil {
+			return nil, fmt.Errorf("composite account %s contains an account without a key", name)
+		}
+		if addresses[acc.Address] {
+			return nil, fmt.Errorf("composite account %s contains the address %s more than once", name, acc.Address)
+		}
+		addresses[acc.Address] = true
+	}
+
+	return &CompositeAccount{
+		Name:      name,
+		accounts:  append([]*Account(nil), accounts...),
+		selection: selection,
+	}, nil
+}
+
+// Accounts returns the accounts of the composite account.
+func (c *CompositeAccount) Accounts() []*Account {
+	return append([]*Account(nil), c.accounts...)
+}
+
+// Select returns the account to use for the next operation.
+func (c *CompositeAccount) Select(ctx context.Context) (*Account, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	problems := make([]string, 0)
+	for i := 0; i < len(c.accounts); i++ {
+		acc := c.accounts[(c.next+i)%len(c.accounts)]
+
+		if c.selection == SelectAvailable {
+			if err := checkAvailable(ctx, acc); err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+		}
+
+		c.next = (c.next + i + 1) % len(c.accounts)
+		return acc, nil
+	}
+
+	return nil, fmt.Errorf("no account of composite account %s is available: %s", c.Name, strings.Join(problems, "; "))
+}
+
+// Account returns the account of the composite account with the address.
+func (c *CompositeAccount) Account(address flow.Address) (*Account, error) {
+	for _, acc := range c.accounts {
+		if acc.Address == address {
+			return acc, nil
+		}
+	}
+	return nil, fmt.Errorf("address %s is not part of composite account %s", address, c.Name)
+}
+
+// SignTransaction signs the transaction for the role with the account of the composite account
+// which has the role in the transaction, usually the account returned by Select.
+func (c *CompositeAccount) SignTransaction(ctx context.Context, tx *flow.Transaction, role SignRole) error {
+	var addresses []flow.Address
+	switch role {
+	case SignRoleProposer:
+		addresses = []flow.Address{tx.ProposalKey.Address}
+	case SignRolePayer:
+		addresses = []flow.Address{tx.Payer}
+	default:
+		addresses = tx.Authorizers
+	}
+
+	for _, address := range addresses {
+		if acc, err := c.Account(address); err == nil {
+			return acc.SignTransaction(ctx, tx, role)
+		}
+	}
+
+	return fmt.Errorf("no account of composite account %s is the transaction %s", c.Name, role)
+}
+
+// checkAvailable checks the account key is ready to sign and has the weight to sign alone.
+func checkAvailable(ctx context.Context, acc *Account) error {
+	ready, err := acc.Key.ReadyToSign(ctx)
+	if err != nil {
+		return fmt.Errorf("account %s is not ready to sign: %w", acc.Name, err)
+	}
+	if !ready {
+		return fmt.Errorf("account %s is not ready to sign", acc.Name)
+	}
+
+	alone, err := acc.CanAuthorizeAlone()
+	if err != nil {
+		return err
+	}
+	if !alone {
+		return fmt.Errorf("account %s key weight %d is below the threshold", acc.Name, acc.Key.Weight())
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_CompositeAccount(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	newAccount := func(name string, address string) *Account {
+		return &Account{
+			Name:    name,
+			Address: flow.HexToAddress(address),
+			Key:     NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+		}
+	}
+	alice, bob, charlie := newAccount("alice", "0x01"), newAccount("bob", "0x02"), newAccount("charlie", "0x03")
+
+	t.Run("Round robin", func(t *testing.T) {
+		composite, err := NewCompositeAccount("funded", []*Account{alice, bob, charlie}, SelectRoundRobin)
+		assert.NoError(t, err)
+
+		for _, expected := range []*Account{alice, bob, charlie, alice} {
+			acc, err := composite.Select(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, expected, acc)
+		}
+	})
+
+	t.Run("Available", func(t *testing.T) {
+		partial := newAccount("partial", "0x04")
+		partial.Key.(*HexKey).weight = 500
+		missing := &Account{Name: "missing", Address: flow.HexToAddress("0x05"), Key: &HexKey{baseKey: &baseKey{}}}
+
+		composite, err := NewCompositeAccount("funded", []*Account{partial, alice, missing, bob}, SelectAvailable)
+		assert.NoError(t, err)
+
+		for _, expected := range []*Account{alice, bob, alice} {
+			acc, err := composite.Select(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, expected, acc)
+		}
+
+		composite, err = NewCompositeAccount("funded", []*Account{partial, missing}, SelectAvailable)
+		assert.NoError(t, err)
+		_, err = composite.Select(context.Background())
+		assert.EqualError(t, err, "no account of composite account funded is available: account partial key weight 500 is below the threshold; account missing is not ready to sign: no private key configured for account")
+	})
+
+	t.Run("Sign transaction", func(t *testing.T) {
+		composite, err := NewCompositeAccount("funded", []*Account{alice, bob}, SelectRoundRobin)
+		assert.NoError(t, err)
+
+		tx := flow.NewTransaction().
+			SetProposalKey(bob.Address, 0, 0).
+			SetPayer(bob.Address)
+
+		err = composite.SignTransaction(context.Background(), tx, SignRolePayer)
+		assert.NoError(t, err)
+		assert.Len(t, tx.EnvelopeSignatures, 1)
+		assert.Equal(t, bob.Address, tx.EnvelopeSignatures[0].Address)
+
+		err = composite.SignTransaction(context.Background(), tx, SignRoleAuthorizer)
+		assert.EqualError(t, err, "no account of composite account funded is the transaction authorizer")
+	})
+
+	t.Run("Fail invalid accounts", func(t *testing.T) {
+		_, err := NewCompositeAccount("funded", nil, SelectRoundRobin)
+		assert.EqualError(t, err, "composite account funded has no accounts")
+
+		_, err = NewCompositeAccount("funded", []*Account{alice, alice}, SelectRoundRobin)
+		assert.EqualError(t, err, "composite account funded contains the address 0000000000000001 more than once")
+
+		_, err = NewCompositeAccount("funded", []*Account{{Name: "empty"}}, SelectRoundRobin)
+		assert.EqualError(t, err, "composite account funded contains an account without a key")
+	})
+}
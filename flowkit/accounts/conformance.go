@@ -0,0 +1,99 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// conformanceVectors are the messages signed by RunSignerConformance, covering an empty message,
+// binary data, a message larger than the hash block size and a domain tagged transaction message.
+var conformanceVectors = [][]byte{
+	{},
+	[]byte("The quick brown fox jumps over the lazy dog"),
+	{0x00, 0x01, 0xfe, 0xff},
+	bytes.Repeat([]byte("flow"), 1024),
+	append(flow.TransactionDomainTag[:], []byte("payload")...),
+}
+
+// RunSignerConformance signs a fixed set of messages with the key and verifies each signature against
+// the public key of the key, which gives confidence a new signer backend is integrated correctly.
+//
+// A signature that doesn't verify using the hash algorithm of the key, but verifies using another hash
+// algorithm compatible with the signature algorithm, is reported as the backend using a wrong hash algorithm.
+func RunSignerConformance(ctx context.Context, key Key) error {
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create the signer: %w", err)
+	}
+
+	publicKey := signer.PublicKey()
+	if publicKey == nil {
+		return fmt.Errorf("signer returned no public key")
+	}
+	if pkey, err := key.PrivateKey(); err == nil && !(*pkey).PublicKey().Equals(publicKey) {
+		return fmt.Errorf("signer public key %s doesn't match the key %s", publicKey, (*pkey).PublicKey())
+	}
+
+	for i, message := range conformanceVectors {
+		signature, err := signer.Sign(message)
+		if err != nil {
+			return fmt.Errorf("failed to sign test vector %d: %w", i, err)
+		}
+
+		valid, err := verifySignature(publicKey, key.HashAlgo(), signature, message)
+		if err != nil {
+			return fmt.Errorf("failed to verify test vector %d: %w", i, err)
+		}
+		if valid {
+			continue
+		}
+
+		for _, hashAlgo := range []crypto.HashAlgorithm{crypto.SHA2_256, crypto.SHA3_256} {
+			if hashAlgo == key.HashAlgo() || !crypto.CompatibleAlgorithms(key.SigAlgo(), hashAlgo) {
+				continue
+			}
+			if valid, _ := verifySignature(publicKey, hashAlgo, signature, message); valid {
+				return fmt.Errorf("signature of test vector %d uses %s instead of %s", i, hashAlgo, key.HashAlgo())
+			}
+		}
+
+		return fmt.Errorf("signature of test vector %d doesn't verify with the public key %s", i, publicKey)
+	}
+
+	return nil
+}
+
+func verifySignature(
+	publicKey crypto.PublicKey,
+	hashAlgo crypto.HashAlgorithm,
+	signature []byte,
+	message []byte,
+) (bool, error) {
+	hasher, err := crypto.NewHasher(hashAlgo)
+	if err != nil {
+		return false, err
+	}
+	return publicKey.Verify(signature, message, hasher)
+}
@@ -0,0 +1,60 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+// signerKey is a key using the provided signer.
+type signerKey struct {
+	Key
+	signer crypto.Signer
+}
+
+func (k signerKey) Signer(_ context.Context) (crypto.Signer, error) {
+	return k.signer, nil
+}
+
+func Test_RunSignerConformance(t *testing.T) {
+	for _, sigAlgo := range []crypto.SignatureAlgorithm{crypto.ECDSA_P256, crypto.ECDSA_secp256k1} {
+		pkey := tests.DecodePrivKey(sigAlgo, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+		for _, hashAlgo := range []crypto.HashAlgorithm{crypto.SHA2_256, crypto.SHA3_256} {
+			key := NewHexKeyFromPrivateKey(0, hashAlgo, pkey)
+			assert.NoError(t, RunSignerConformance(context.Background(), key))
+		}
+	}
+
+	t.Run("Wrong hash algorithm", func(t *testing.T) {
+		pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+		signer, err := crypto.NewInMemorySigner(pkey, crypto.SHA2_256)
+		assert.NoError(t, err)
+		key := signerKey{NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), signer}
+		err = RunSignerConformance(context.Background(), key)
+		assert.EqualError(t, err, "signature of test vector 0 uses SHA2_256 instead of SHA3_256")
+	})
+}
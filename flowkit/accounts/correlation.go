@@ -0,0 +1,56 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// CorrelationIDHeader is the header, or the gRPC metadata key in lower case, used to send the correlation ID
+// with the remote signing requests.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID.
+//
+// Signers of remote keys created with the context send the ID with every signing request,
+// so signatures can be matched with the entries in the audit log of the signing service.
+// Signers of local keys ignore it.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of the context or an empty string if not set.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// withCorrelationMetadata adds the correlation ID of the context to the outgoing gRPC metadata.
+func withCorrelationMetadata(ctx context.Context) context.Context {
+	id := CorrelationID(ctx)
+	if id == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, CorrelationIDHeader, id)
+}
@@ -0,0 +1,87 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+func Test_CorrelationID(t *testing.T) {
+	assert.Equal(t, "", CorrelationID(context.Background()))
+	ctx := WithCorrelationID(context.Background(), "request-1")
+	assert.Equal(t, "request-1", CorrelationID(ctx))
+
+	t.Run("Remote HTTP", func(t *testing.T) {
+		pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, make([]byte, crypto.MinSeedLength))
+		assert.NoError(t, err)
+
+		var ids []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ids = append(ids, r.Header.Get(CorrelationIDHeader))
+			switch r.URL.Path {
+			case "/keys/alice":
+				_ = json.NewEncoder(w).Encode(map[string]string{"publicKey": pkey.PublicKey().String()})
+			case "/keys/alice/sign":
+				_ = json.NewEncoder(w).Encode(map[string]string{"signature": hex.EncodeToString(make([]byte, 64))})
+			}
+		}))
+		defer server.Close()
+
+		key, err := remoteHTTPKeyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeRemoteHTTP,
+			SigAlgo:    crypto.ECDSA_P256,
+			HashAlgo:   crypto.SHA3_256,
+			ResourceID: "alice",
+			Endpoint:   server.URL,
+		})
+		assert.NoError(t, err)
+
+		signer, err := key.Signer(ctx)
+		assert.NoError(t, err)
+		_, err = signer.Sign([]byte("message"))
+		assert.NoError(t, err)
+
+		signer, err = key.Signer(context.Background())
+		assert.NoError(t, err)
+		_, err = signer.Sign([]byte("message"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"request-1", "request-1", "", ""}, ids)
+	})
+
+	t.Run("gRPC metadata", func(t *testing.T) {
+		md, ok := metadata.FromOutgoingContext(withCorrelationMetadata(ctx))
+		assert.True(t, ok)
+		assert.Equal(t, []string{"request-1"}, md.Get(CorrelationIDHeader))
+
+		_, ok = metadata.FromOutgoingContext(withCorrelationMetadata(context.Background()))
+		assert.False(t, ok)
+	})
+}
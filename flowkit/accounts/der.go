@@ -0,0 +1,114 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"encoding/asn1"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+var (
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidP256        = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// pkcs8 is the PKCS#8 private key structure defined in RFC 5208.
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkcs8Algorithm
+	PrivateKey []byte
+}
+
+type pkcs8Algorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier `asn1:"optional"`
+}
+
+// ecPrivateKey is the SEC 1 EC private key structure defined in RFC 5915.
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// HexKeyFromDER creates a hex key from the DER encoded EC private key, either in the PKCS#8 or the SEC 1 format.
+//
+// The signature algorithm is inferred from the curve of the key, which must be P-256 or secp256k1.
+func HexKeyFromDER(der []byte, hashAlgo crypto.HashAlgorithm) (*HexKey, error) {
+	curve, scalar, err := parseECPrivateKeyDER(der)
+	if err != nil {
+		return nil, err
+	}
+
+	var sigAlgo crypto.SignatureAlgorithm
+	switch {
+	case curve.Equal(oidP256):
+		sigAlgo = crypto.ECDSA_P256
+	case curve.Equal(oidSecp256k1):
+		sigAlgo = crypto.ECDSA_secp256k1
+	default:
+		return nil, fmt.Errorf("unsupported curve %s, only P-256 and secp256k1 keys are supported", curve)
+	}
+
+	// the scalar is encoded without leading zero bytes by some tools
+	if len(scalar) > 32 {
+		return nil, fmt.Errorf("invalid private key length %d", len(scalar))
+	}
+	padded := make([]byte, 32)
+	copy(padded[32-len(scalar):], scalar)
+
+	privateKey, err := crypto.DecodePrivateKey(sigAlgo, padded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return NewHexKeyFromPrivateKey(0, hashAlgo, privateKey), nil
+}
+
+// parseECPrivateKeyDER returns the curve and the private scalar of the DER encoded EC private key.
+func parseECPrivateKeyDER(der []byte) (asn1.ObjectIdentifier, []byte, error) {
+	var p8 pkcs8
+	if rest, err := asn1.Unmarshal(der, &p8); err == nil && len(rest) == 0 {
+		if !p8.Algorithm.Algorithm.Equal(oidECPublicKey) {
+			return nil, nil, fmt.Errorf("not an EC private key, algorithm %s", p8.Algorithm.Algorithm)
+		}
+
+		var key ecPrivateKey
+		if _, err := asn1.Unmarshal(p8.PrivateKey, &key); err != nil {
+			return nil, nil, fmt.Errorf("invalid EC private key: %w", err)
+		}
+
+		return p8.Algorithm.Parameters, key.PrivateKey, nil
+	}
+
+	var key ecPrivateKey
+	rest, err := asn1.Unmarshal(der, &key)
+	if err != nil || len(rest) != 0 {
+		return nil, nil, fmt.Errorf("invalid DER encoded private key, expected a PKCS#8 or SEC 1 EC private key")
+	}
+	if key.NamedCurveOID == nil {
+		return nil, nil, fmt.Errorf("EC private key doesn't specify the curve")
+	}
+
+	return key.NamedCurveOID, key.PrivateKey, nil
+}
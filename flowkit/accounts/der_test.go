@@ -0,0 +1,92 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HexKeyFromDER(t *testing.T) {
+	const privateKey = "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+	scalar, _ := hex.DecodeString(privateKey)
+
+	ecKey := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(scalar)}
+	ecKey.Curve = elliptic.P256()
+	ecKey.X, ecKey.Y = ecKey.Curve.ScalarBaseMult(scalar)
+
+	t.Run("PKCS8 P256", func(t *testing.T) {
+		der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+		assert.NoError(t, err)
+
+		key, err := HexKeyFromDER(der, crypto.SHA3_256)
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.ECDSA_P256, key.SigAlgo())
+		assert.Equal(t, crypto.SHA3_256, key.HashAlgo())
+		assert.Equal(t, privateKey, key.privateKeyHex())
+	})
+
+	t.Run("SEC1 P256", func(t *testing.T) {
+		der, err := x509.MarshalECPrivateKey(ecKey)
+		assert.NoError(t, err)
+
+		key, err := HexKeyFromDER(der, crypto.SHA3_256)
+		assert.NoError(t, err)
+		assert.Equal(t, privateKey, key.privateKeyHex())
+	})
+
+	t.Run("PKCS8 secp256k1", func(t *testing.T) {
+		inner, err := asn1.Marshal(ecPrivateKey{Version: 1, PrivateKey: scalar})
+		assert.NoError(t, err)
+		der, err := asn1.Marshal(pkcs8{
+			Algorithm:  pkcs8Algorithm{Algorithm: oidECPublicKey, Parameters: oidSecp256k1},
+			PrivateKey: inner,
+		})
+		assert.NoError(t, err)
+
+		key, err := HexKeyFromDER(der, crypto.SHA2_256)
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.ECDSA_secp256k1, key.SigAlgo())
+		assert.Equal(t, privateKey, key.privateKeyHex())
+	})
+
+	t.Run("Unsupported curve", func(t *testing.T) {
+		p384 := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(scalar)}
+		p384.Curve = elliptic.P384()
+		p384.X, p384.Y = p384.Curve.ScalarBaseMult(scalar)
+		der, err := x509.MarshalPKCS8PrivateKey(p384)
+		assert.NoError(t, err)
+
+		_, err = HexKeyFromDER(der, crypto.SHA3_256)
+		assert.EqualError(t, err, "unsupported curve 1.3.132.0.34, only P-256 and secp256k1 keys are supported")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := HexKeyFromDER([]byte{1, 2, 3}, crypto.SHA3_256)
+		assert.EqualError(t, err, "invalid DER encoded private key, expected a PKCS#8 or SEC 1 EC private key")
+	})
+}
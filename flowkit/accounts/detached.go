@@ -0,0 +1,136 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// DetachedSignature is a signature created separately from the message, for example on an
+// air-gapped machine, together with the information needed to verify it later.
+type DetachedSignature struct {
+	Signature   []byte
+	Fingerprint string
+	SigAlgo     crypto.SignatureAlgorithm
+	HashAlgo    crypto.HashAlgorithm
+	KeyIndex    int
+}
+
+type detachedSignatureJSON struct {
+	Signature   string `json:"signature"`
+	Fingerprint string `json:"fingerprint"`
+	SigAlgo     string `json:"signatureAlgorithm"`
+	HashAlgo    string `json:"hashAlgorithm"`
+	KeyIndex    int    `json:"keyIndex"`
+}
+
+func (d DetachedSignature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(detachedSignatureJSON{
+		Signature:   hex.EncodeToString(d.Signature),
+		Fingerprint: d.Fingerprint,
+		SigAlgo:     d.SigAlgo.String(),
+		HashAlgo:    d.HashAlgo.String(),
+		KeyIndex:    d.KeyIndex,
+	})
+}
+
+func (d *DetachedSignature) UnmarshalJSON(b []byte) error {
+	var raw detachedSignatureJSON
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(raw.Signature, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid detached signature: %w", err)
+	}
+
+	*d = DetachedSignature{
+		Signature:   signature,
+		Fingerprint: raw.Fingerprint,
+		SigAlgo:     crypto.StringToSignatureAlgorithm(raw.SigAlgo),
+		HashAlgo:    crypto.StringToHashAlgorithm(raw.HashAlgo),
+		KeyIndex:    raw.KeyIndex,
+	}
+	return nil
+}
+
+// publicKeyFingerprint returns the hex encoded SHA-256 hash of the public key.
+func publicKeyFingerprint(publicKey crypto.PublicKey) string {
+	hash := sha256.Sum256(publicKey.Encode())
+	return hex.EncodeToString(hash[:])
+}
+
+// SignDetached signs the message with the key and returns the detached signature.
+func SignDetached(ctx context.Context, key Key, message []byte) (*DetachedSignature, error) {
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.Sign(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DetachedSignature{
+		Signature:   signature,
+		Fingerprint: publicKeyFingerprint(signer.PublicKey()),
+		SigAlgo:     key.SigAlgo(),
+		HashAlgo:    key.HashAlgo(),
+		KeyIndex:    key.Index(),
+	}, nil
+}
+
+// VerifyDetached verifies the detached signature of the message was created by the public key.
+func VerifyDetached(publicKey crypto.PublicKey, message []byte, signature *DetachedSignature) error {
+	if publicKeyFingerprint(publicKey) != signature.Fingerprint {
+		return fmt.Errorf("signature was created by a different key with fingerprint %s", signature.Fingerprint)
+	}
+
+	if publicKey.Algorithm() != signature.SigAlgo {
+		return fmt.Errorf(
+			"signature algorithm %s does not match the public key algorithm %s",
+			signature.SigAlgo, publicKey.Algorithm(),
+		)
+	}
+
+	hasher, err := crypto.NewHasher(signature.HashAlgo)
+	if err != nil {
+		return err
+	}
+
+	valid, err := publicKey.Verify(signature.Signature, message, hasher)
+	if err != nil {
+		return fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("signature is not valid for the message")
+	}
+
+	return nil
+}
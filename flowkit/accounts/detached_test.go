@@ -0,0 +1,77 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DetachedSignature(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	other, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+	assert.NoError(t, err)
+	publicKey := pkey.PublicKey()
+	message := []byte("transfer 10 FLOW")
+
+	key := NewHexKeyFromPrivateKey(2, crypto.SHA2_256, pkey)
+	signature, err := SignDetached(context.Background(), key, message)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, signature.KeyIndex)
+	assert.Equal(t, crypto.ECDSA_P256, signature.SigAlgo)
+	assert.Equal(t, crypto.SHA2_256, signature.HashAlgo)
+
+	raw, err := json.Marshal(signature)
+	assert.NoError(t, err)
+
+	var restored DetachedSignature
+	assert.NoError(t, json.Unmarshal(raw, &restored))
+	assert.Equal(t, *signature, restored)
+
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(raw, &fields))
+	assert.Equal(t, "ECDSA_P256", fields["signatureAlgorithm"])
+	assert.Equal(t, "SHA2_256", fields["hashAlgorithm"])
+
+	t.Run("Verify", func(t *testing.T) {
+		assert.NoError(t, VerifyDetached(publicKey, message, &restored))
+	})
+
+	t.Run("Fail other message", func(t *testing.T) {
+		err := VerifyDetached(publicKey, []byte("transfer 1000 FLOW"), &restored)
+		assert.EqualError(t, err, "signature is not valid for the message")
+	})
+
+	t.Run("Fail other key", func(t *testing.T) {
+		err := VerifyDetached(other.PublicKey(), message, &restored)
+		assert.ErrorContains(t, err, "signature was created by a different key")
+	})
+
+	t.Run("Fail other hash algorithm", func(t *testing.T) {
+		tampered := restored
+		tampered.HashAlgo = crypto.SHA3_256
+		err := VerifyDetached(publicKey, message, &tampered)
+		assert.EqualError(t, err, "signature is not valid for the message")
+	})
+}
@@ -0,0 +1,169 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"golang.org/x/crypto/sha3"
+)
+
+var deterministicSigning bool
+
+// SetDeterministicSigning enables the deterministic mode in which keys with an in-memory private key sign using
+// deterministic ECDSA as specified in RFC 6979, so signing the same message always results in the same signature.
+//
+// Flow ECDSA signatures are randomized by default, signing the same message twice results in different, equally
+// valid, signatures. The deterministic mode is useful for golden tests comparing signatures.
+func SetDeterministicSigning(deterministic bool) {
+	deterministicSigning = deterministic
+}
+
+var _ crypto.Signer = &DeterministicSigner{}
+
+// DeterministicSigner signs using deterministic ECDSA as specified in RFC 6979.
+//
+// Only ECDSA_P256 and ECDSA_secp256k1 keys with the SHA2_256 or SHA3_256 hash algorithm are supported,
+// the nonce is derived using HMAC with the hash algorithm of the signature.
+type DeterministicSigner struct {
+	privateKey crypto.PrivateKey
+	hashAlgo   crypto.HashAlgorithm
+	curve      elliptic.Curve
+	hmacHash   func() hash.Hash
+}
+
+// NewDeterministicSigner creates a deterministic signer for the private key and the hash algorithm.
+func NewDeterministicSigner(privateKey crypto.PrivateKey, hashAlgo crypto.HashAlgorithm) (*DeterministicSigner, error) {
+	signer := &DeterministicSigner{
+		privateKey: privateKey,
+		hashAlgo:   hashAlgo,
+	}
+
+	switch privateKey.Algorithm() {
+	case crypto.ECDSA_P256:
+		signer.curve = elliptic.P256()
+	case crypto.ECDSA_secp256k1:
+		signer.curve = btcec.S256()
+	default:
+		return nil, fmt.Errorf("deterministic signing is not supported for %s keys", privateKey.Algorithm())
+	}
+
+	switch hashAlgo {
+	case crypto.SHA2_256:
+		signer.hmacHash = sha256.New
+	case crypto.SHA3_256:
+		signer.hmacHash = sha3.New256
+	default:
+		return nil, fmt.Errorf("deterministic signing is not supported with the %s hash algorithm", hashAlgo)
+	}
+
+	return signer, nil
+}
+
+func (s *DeterministicSigner) Sign(message []byte) ([]byte, error) {
+	hasher, err := crypto.NewHasher(s.hashAlgo)
+	if err != nil {
+		return nil, err
+	}
+	digest := hasher.ComputeHash(message)
+
+	n := s.curve.Params().N
+	d := new(big.Int).SetBytes(s.privateKey.Encode())
+	e := new(big.Int).SetBytes(digest)
+
+	nonces := s.nonces(d, e)
+	for {
+		k := nonces()
+
+		x, _ := s.curve.ScalarBaseMult(k.FillBytes(make([]byte, 32)))
+		r := new(big.Int).Mod(x, n)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		// s = k^-1 * (e + r * d) mod n
+		sig := new(big.Int).Mul(r, d)
+		sig.Add(sig, e)
+		sig.Mul(sig, new(big.Int).ModInverse(k, n))
+		sig.Mod(sig, n)
+		if sig.Sign() == 0 {
+			continue
+		}
+
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		sig.FillBytes(signature[32:])
+		return signature, nil
+	}
+}
+
+func (s *DeterministicSigner) PublicKey() crypto.PublicKey {
+	return s.privateKey.PublicKey()
+}
+
+// nonces returns the generator of the nonce candidates as specified in RFC 6979 section 3.2,
+// both supported curves have a 256 bit order so no bit truncation is needed.
+func (s *DeterministicSigner) nonces(d *big.Int, e *big.Int) func() *big.Int {
+	n := s.curve.Params().N
+	x := d.FillBytes(make([]byte, 32))
+	h := new(big.Int).Mod(e, n).FillBytes(make([]byte, 32))
+
+	mac := func(key []byte, data ...[]byte) []byte {
+		m := hmac.New(s.hmacHash, key)
+		for _, b := range data {
+			m.Write(b)
+		}
+		return m.Sum(nil)
+	}
+
+	v := make([]byte, 32)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, 32)
+
+	k = mac(k, v, []byte{0x00}, x, h)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h)
+	v = mac(k, v)
+
+	first := true
+	return func() *big.Int {
+		for {
+			if !first {
+				k = mac(k, v, []byte{0x00})
+				v = mac(k, v)
+			}
+			first = false
+
+			v = mac(k, v)
+			candidate := new(big.Int).SetBytes(v)
+			if candidate.Sign() > 0 && candidate.Cmp(n) < 0 {
+				return candidate
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_DeterministicSigner(t *testing.T) {
+	t.Run("RFC 6979 test vector", func(t *testing.T) {
+		// test vector from RFC 6979 A.2.5, P-256 with SHA-256 and the message "sample"
+		pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+
+		signer, err := NewDeterministicSigner(pkey, crypto.SHA2_256)
+		assert.NoError(t, err)
+
+		sig, err := signer.Sign([]byte("sample"))
+		assert.NoError(t, err)
+		assert.Equal(
+			t,
+			"efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716"+
+				"f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8",
+			hex.EncodeToString(sig),
+		)
+	})
+
+	t.Run("Deterministic mode", func(t *testing.T) {
+		SetDeterministicSigning(true)
+		defer SetDeterministicSigning(false)
+
+		message := []byte("message")
+		for _, sigAlgo := range []crypto.SignatureAlgorithm{crypto.ECDSA_P256, crypto.ECDSA_secp256k1} {
+			for _, hashAlgo := range []crypto.HashAlgorithm{crypto.SHA2_256, crypto.SHA3_256} {
+				pkey, err := crypto.DecodePrivateKeyHex(sigAlgo, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+				assert.NoError(t, err)
+				publicKey := pkey.PublicKey()
+
+				signer, err := NewHexKeyFromPrivateKey(0, hashAlgo, pkey).Signer(context.Background())
+				assert.NoError(t, err)
+
+				first, err := signer.Sign(message)
+				assert.NoError(t, err)
+				second, err := signer.Sign(message)
+				assert.NoError(t, err)
+				assert.Equal(t, first, second)
+
+				hasher, err := crypto.NewHasher(hashAlgo)
+				assert.NoError(t, err)
+				valid, err := publicKey.Verify(first, message, hasher)
+				assert.NoError(t, err)
+				assert.True(t, valid)
+			}
+		}
+	})
+
+	t.Run("Unsupported hash algorithm", func(t *testing.T) {
+		pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+		assert.NoError(t, err)
+
+		_, err = NewDeterministicSigner(pkey, crypto.SHA2_384)
+		assert.EqualError(t, err, "deterministic signing is not supported with the SHA2_384 hash algorithm")
+	})
+}
@@ -0,0 +1,161 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// PassphraseEnv is the environment variable containing the passphrase used to decrypt encrypted keys.
+const PassphraseEnv = "FLOW_KEYS_PASSPHRASE"
+
+// scrypt parameters used when encrypting new secrets.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// maximum scrypt parameters accepted when decrypting, the parameters are read from the configuration
+// and unbounded values could force deriving the key to use excessive memory and time.
+const (
+	maxScryptN = 1 << 20
+	maxScryptR = 8
+	maxScryptP = 16
+)
+
+var secretPrompt func(prompt string) (string, error)
+
+// SetSecretPrompt sets the function called when a key needs a secret which is not configured,
+// such as the passphrase of an encrypted key, the prompt describes the requested secret.
+//
+// Flowkit never reads from the terminal itself, interactive callers can ask the user while
+// other callers can read the secret from a secret manager or return an error.
+func SetSecretPrompt(prompt func(prompt string) (string, error)) {
+	secretPrompt = prompt
+}
+
+// passphrase returns the passphrase from the environment or the secret prompt if set.
+func passphrase() (string, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return p, nil
+	}
+
+	if secretPrompt == nil {
+		return "", fmt.Errorf("passphrase for encrypted key not provided, set the %s environment variable", PassphraseEnv)
+	}
+
+	return secretPrompt("Passphrase for the encrypted key")
+}
+
+// passphraseAvailable checks the passphrase can be obtained without reading it.
+func passphraseAvailable() (bool, error) {
+	if os.Getenv(PassphraseEnv) == "" && secretPrompt == nil {
+		return false, fmt.Errorf("passphrase for encrypted key not provided, set the %s environment variable", PassphraseEnv)
+	}
+	return true, nil
+}
+
+// EncryptSecret encrypts the secret with a key derived from the passphrase.
+func EncryptSecret(secret []byte, passphrase string) (*config.EncryptedSecret, error) {
+	salt := make([]byte, 16)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	encrypted := &config.EncryptedSecret{
+		Salt: salt,
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+	}
+
+	gcm, err := secretCipher(encrypted, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted.Nonce = make([]byte, gcm.NonceSize())
+	_, err = rand.Read(encrypted.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	encrypted.Ciphertext = gcm.Seal(nil, encrypted.Nonce, secret, nil)
+	return encrypted, nil
+}
+
+// DecryptSecret decrypts the secret with a key derived from the passphrase.
+func DecryptSecret(encrypted *config.EncryptedSecret, passphrase string) ([]byte, error) {
+	gcm, err := secretCipher(encrypted, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(encrypted.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length for encrypted key")
+	}
+
+	secret, err := gcm.Open(nil, encrypted.Nonce, encrypted.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt key, make sure the passphrase is correct")
+	}
+
+	return secret, nil
+}
+
+// decryptSecret decrypts the secret using the passphrase from the environment or the prompt.
+func decryptSecret(encrypted *config.EncryptedSecret) ([]byte, error) {
+	p, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecryptSecret(encrypted, p)
+}
+
+func secretCipher(encrypted *config.EncryptedSecret, passphrase string) (cipher.AEAD, error) {
+	if encrypted.N > maxScryptN || encrypted.R > maxScryptR || encrypted.P > maxScryptP {
+		return nil, fmt.Errorf(
+			"scrypt parameters N=%d, r=%d, p=%d exceed the maximum N=%d, r=%d, p=%d",
+			encrypted.N, encrypted.R, encrypted.P, maxScryptN, maxScryptR, maxScryptP,
+		)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), encrypted.Salt, encrypted.N, encrypted.R, encrypted.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,146 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+func Test_EncryptSecret(t *testing.T) {
+	encrypted, err := EncryptSecret([]byte("secret"), "passphrase")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encrypted.Ciphertext), "secret")
+
+	secret, err := DecryptSecret(encrypted, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(secret))
+
+	_, err = DecryptSecret(encrypted, "wrong")
+	assert.EqualError(t, err, "failed to decrypt key, make sure the passphrase is correct")
+}
+
+func Test_DecryptSecretParamsLimit(t *testing.T) {
+	encrypted, err := EncryptSecret([]byte("secret"), "passphrase")
+	assert.NoError(t, err)
+
+	encrypted.N = 1 << 30
+	_, err = DecryptSecret(encrypted, "passphrase")
+	assert.EqualError(t, err, "scrypt parameters N=1073741824, r=8, p=1 exceed the maximum N=1048576, r=8, p=16")
+
+	encrypted.N = scryptN
+	encrypted.P = 1 << 20
+	_, err = DecryptSecret(encrypted, "passphrase")
+	assert.EqualError(t, err, "scrypt parameters N=32768, r=8, p=1048576 exceed the maximum N=1048576, r=8, p=16")
+}
+
+func Test_EncryptedKeys(t *testing.T) {
+	const privateKey = "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad1111a"
+	const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+
+	encryptedKey, err := EncryptSecret([]byte(privateKey), "passphrase")
+	assert.NoError(t, err)
+	encryptedMnemonic, err := EncryptSecret([]byte(mnemonic), "passphrase")
+	assert.NoError(t, err)
+
+	t.Run("Hex key", func(t *testing.T) {
+		t.Setenv(PassphraseEnv, "passphrase")
+		confKey := config.AccountKey{
+			Type:      config.KeyTypeHex,
+			SigAlgo:   config.DefaultSigAlgo,
+			HashAlgo:  config.DefaultHashAlgo,
+			Encrypted: encryptedKey,
+		}
+
+		key, err := hexKeyFromConfig(confKey)
+		assert.NoError(t, err)
+		assert.NoError(t, key.Validate())
+
+		pkey, err := key.PrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, "0x"+privateKey, (*pkey).String())
+		assert.Equal(t, confKey, key.ToConfig())
+	})
+
+	t.Run("BIP44 key", func(t *testing.T) {
+		t.Setenv(PassphraseEnv, "passphrase")
+		confKey := config.AccountKey{
+			Type:           config.KeyTypeBip44,
+			SigAlgo:        config.DefaultSigAlgo,
+			HashAlgo:       config.DefaultHashAlgo,
+			DerivationPath: "m/44'/539'/0'/0/0",
+			Encrypted:      encryptedMnemonic,
+		}
+
+		key, err := bip44KeyFromConfig(confKey)
+		assert.NoError(t, err)
+
+		signer, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be", signer.PublicKey().String())
+		assert.Equal(t, confKey, key.ToConfig())
+	})
+
+	t.Run("Passphrase prompt", func(t *testing.T) {
+		t.Setenv(PassphraseEnv, "")
+		key, err := hexKeyFromConfig(config.AccountKey{Type: config.KeyTypeHex, Encrypted: encryptedKey})
+		assert.NoError(t, err)
+
+		_, err = key.PrivateKey()
+		assert.EqualError(t, err, "passphrase for encrypted key not provided, set the FLOW_KEYS_PASSPHRASE environment variable")
+
+		SetSecretPrompt(func(string) (string, error) {
+			return "passphrase", nil
+		})
+		defer SetSecretPrompt(nil)
+
+		_, err = key.PrivateKey()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Secret prompt", func(t *testing.T) {
+		t.Setenv(PassphraseEnv, "")
+		key, err := hexKeyFromConfig(config.AccountKey{Type: config.KeyTypeHex, Encrypted: encryptedKey})
+		assert.NoError(t, err)
+
+		var prompts []string
+		SetSecretPrompt(func(prompt string) (string, error) {
+			prompts = append(prompts, prompt)
+			return "", fmt.Errorf("no secret manager configured")
+		})
+		defer SetSecretPrompt(nil)
+
+		_, err = key.PrivateKey()
+		assert.EqualError(t, err, "no secret manager configured")
+
+		SetSecretPrompt(func(prompt string) (string, error) {
+			prompts = append(prompts, prompt)
+			return "passphrase", nil
+		})
+
+		_, err = key.PrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Passphrase for the encrypted key", "Passphrase for the encrypted key"}, prompts)
+	})
+}
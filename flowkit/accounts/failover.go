@@ -0,0 +1,121 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// FailoverSigner signs transactions for an account with the account key and falls back to a backup key
+// registered on the same account when signing with the account key fails, such as during a KMS outage.
+//
+// The backup key is usually a local key kept for emergencies, so automated submission keeps working
+// while the remote signer is unavailable.
+type FailoverSigner struct {
+	account *Account
+	backup  Key
+}
+
+// FailoverSigner creates a signer falling back to the backup key, the backup key must be registered
+// at a different index and have at least the weight of the account key so it can replace it.
+func (a *Account) FailoverSigner(backup Key) (*FailoverSigner, error) {
+	if a.Key == nil {
+		return nil, fmt.Errorf("account %s is missing the key", a.Name)
+	}
+	if backup == nil {
+		return nil, fmt.Errorf("backup key of account %s is missing", a.Name)
+	}
+	if backup.Index() == a.Key.Index() {
+		return nil, fmt.Errorf("backup key of account %s uses the index %d of the account key", a.Name, backup.Index())
+	}
+	if backup.Weight() < a.Key.Weight() {
+		return nil, fmt.Errorf(
+			"backup key of account %s has weight %d which is lower than the account key weight %d",
+			a.Name,
+			backup.Weight(),
+			a.Key.Weight(),
+		)
+	}
+
+	return &FailoverSigner{
+		account: a,
+		backup:  backup,
+	}, nil
+}
+
+// SignTransaction signs the transaction for the role and returns the key that signed it.
+//
+// The account key signs at the index used for the role, if creating its signer or signing fails the
+// backup key signs at its own index instead. A denial of the signing policy is never retried with the backup key.
+//
+// The proposer signature must be made by the proposal key, so the backup key only signs as the proposer
+// if the proposal key of the transaction already uses the backup index.
+func (f *FailoverSigner) SignTransaction(ctx context.Context, tx *flow.Transaction, role SignRole) (Key, error) {
+	acc := f.account
+	if err := acc.checkSigning(tx, role); err != nil {
+		return nil, err
+	}
+
+	err := f.sign(ctx, tx, role, acc.Key, acc.RoleKeyIndex(role))
+	if err == nil {
+		return acc.Key, nil
+	}
+
+	if role == SignRoleProposer && tx.ProposalKey.KeyIndex != f.backup.Index() {
+		return nil, fmt.Errorf(
+			"failed to sign with the key of account %s: %w, the backup key can't sign as proposer since the proposal key uses the index %d instead of the backup index %d",
+			acc.Name,
+			err,
+			tx.ProposalKey.KeyIndex,
+			f.backup.Index(),
+		)
+	}
+
+	backupErr := f.sign(ctx, tx, role, f.backup, f.backup.Index())
+	if backupErr != nil {
+		return nil, fmt.Errorf(
+			"failed to sign with the key of account %s: %s, and with the backup key: %w",
+			acc.Name,
+			err,
+			backupErr,
+		)
+	}
+
+	config.Warn(fmt.Sprintf(
+		"signing with the key of account %s failed, signed with the backup key at index %d: %s",
+		acc.Name,
+		f.backup.Index(),
+		err,
+	))
+	return f.backup, nil
+}
+
+func (f *FailoverSigner) sign(ctx context.Context, tx *flow.Transaction, role SignRole, key Key, index int) error {
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return err
+	}
+
+	return f.account.attachSignature(tx, role, index, signer)
+}
@@ -0,0 +1,132 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_FailoverSigner(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	backupKey := tests.DecodePrivKey(crypto.ECDSA_P256, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+
+	address := flow.HexToAddress("01")
+	backup := NewHexKeyFromPrivateKey(1, crypto.SHA3_256, backupKey)
+	newTx := func() *flow.Transaction {
+		return flow.NewTransaction().
+			SetScript([]byte("transaction {}")).
+			SetProposalKey(address, 0, 1).
+			SetPayer(address)
+	}
+
+	t.Run("Account key", func(t *testing.T) {
+		acc := &Account{Name: "test", Address: address, Key: NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)}
+		signer, err := acc.FailoverSigner(backup)
+		assert.NoError(t, err)
+
+		tx := newTx()
+		key, err := signer.SignTransaction(context.Background(), tx, SignRolePayer)
+		assert.NoError(t, err)
+		assert.Equal(t, acc.Key, key)
+		assert.Equal(t, 0, tx.EnvelopeSignatures[0].KeyIndex)
+	})
+
+	t.Run("Backup key", func(t *testing.T) {
+		failing := NewChaosKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), ChaosOptions{FailureRate: 1})
+		acc := &Account{Name: "test", Address: address, Key: failing}
+		signer, err := acc.FailoverSigner(backup)
+		assert.NoError(t, err)
+
+		tx := newTx()
+		key, err := signer.SignTransaction(context.Background(), tx, SignRolePayer)
+		assert.NoError(t, err)
+		assert.Equal(t, Key(backup), key)
+		assert.Len(t, tx.EnvelopeSignatures, 1)
+		assert.Equal(t, 1, tx.EnvelopeSignatures[0].KeyIndex)
+		assert.NoError(t, VerifyTransactionSignatures(context.Background(), tx, []*Account{{Name: "backup", Address: address, Key: backup}}))
+	})
+
+	t.Run("Both keys fail", func(t *testing.T) {
+		failing := NewChaosKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), ChaosOptions{FailureRate: 1})
+		acc := &Account{Name: "test", Address: address, Key: failing}
+		signer, err := acc.FailoverSigner(NewChaosKey(backup, ChaosOptions{FailureRate: 1}))
+		assert.NoError(t, err)
+
+		_, err = signer.SignTransaction(context.Background(), newTx(), SignRolePayer)
+		assert.ErrorContains(t, err, "failed to sign with the key of account test")
+		assert.ErrorContains(t, err, "and with the backup key")
+	})
+
+	t.Run("Proposer", func(t *testing.T) {
+		failing := NewChaosKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), ChaosOptions{FailureRate: 1})
+		acc := &Account{Name: "test", Address: address, Key: failing}
+		signer, err := acc.FailoverSigner(backup)
+		assert.NoError(t, err)
+
+		tx := newTx()
+		_, err = signer.SignTransaction(context.Background(), tx, SignRoleProposer)
+		assert.ErrorContains(t, err, "the backup key can't sign as proposer since the proposal key uses the index 0 instead of the backup index 1")
+		assert.Empty(t, tx.PayloadSignatures)
+
+		tx = newTx().SetProposalKey(address, 1, 1)
+		key, err := signer.SignTransaction(context.Background(), tx, SignRoleProposer)
+		assert.NoError(t, err)
+		assert.Equal(t, Key(backup), key)
+		assert.Equal(t, 1, tx.PayloadSignatures[0].KeyIndex)
+	})
+
+	t.Run("Policy denial is not retried", func(t *testing.T) {
+		acc := &Account{
+			Name:    "test",
+			Address: address,
+			Key:     NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+			SigningPolicy: func(*flow.Transaction, SignRole) error {
+				return errors.New("denied")
+			},
+		}
+		signer, err := acc.FailoverSigner(backup)
+		assert.NoError(t, err)
+
+		tx := newTx()
+		_, err = signer.SignTransaction(context.Background(), tx, SignRolePayer)
+		assert.EqualError(t, err, "signing policy of account test denied signing as payer: denied")
+		assert.Empty(t, tx.EnvelopeSignatures)
+	})
+
+	t.Run("Invalid backup key", func(t *testing.T) {
+		acc := &Account{Name: "test", Address: address, Key: NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey)}
+		_, err := acc.FailoverSigner(backup)
+		assert.EqualError(t, err, "backup key of account test uses the index 1 of the account key")
+
+		weak, err := keyFromConfig(backup.ToConfig())
+		assert.NoError(t, err)
+		weak.(*HexKey).weight = 500
+		acc.Key = NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+		_, err = acc.FailoverSigner(weak)
+		assert.EqualError(t, err, "backup key of account test has weight 500 which is lower than the account key weight 1000")
+	})
+}
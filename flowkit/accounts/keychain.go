@@ -0,0 +1,181 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+var _ Key = &KeychainKey{}
+
+// KeychainKey is a key with the private key stored in the OS credential store.
+//
+// The entry is identified by the service and account name, on macOS it is read from the Keychain
+// and on Linux from the Secret Service (e.g. GNOME Keyring) using the secret-tool utility.
+// The entry is read once and cached, loading is safe for concurrent use.
+type KeychainKey struct {
+	*baseKey
+	mu         sync.Mutex
+	service    string
+	account    string
+	privateKey crypto.PrivateKey
+}
+
+func keychainKeyFromConfig(key config.AccountKey) (*KeychainKey, error) {
+	if key.KeychainService == "" || key.KeychainAccount == "" {
+		return nil, fmt.Errorf("missing service or account for the keychain key")
+	}
+
+	return &KeychainKey{
+		baseKey: baseKeyFromConfig(key),
+		service: key.KeychainService,
+		account: key.KeychainAccount,
+	}, nil
+}
+
+func (k *KeychainKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := k.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, k)
+	}
+
+	key, err := k.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemorySigner(*key, k.HashAlgo())
+}
+
+func (k *KeychainKey) PrivateKey() (*crypto.PrivateKey, error) {
+	k.mu.Lock() // the entry is only read once even if signers are created concurrently
+	defer k.mu.Unlock()
+
+	if k.privateKey == nil { // lazy load the key
+		secret, err := keychainSecret(k.service, k.account)
+		if err != nil {
+			return nil, err
+		}
+		pkey, err := config.DecodePrivateKeyHex(k.SigAlgo(), secret)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode the key from the keychain entry %s/%s: %w", k.service, k.account, err)
+		}
+		k.privateKey = pkey
+	}
+	return &k.privateKey, nil
+}
+
+func (k *KeychainKey) Validate() error {
+	return k.ValidateCtx(context.Background())
+}
+
+func (k *KeychainKey) ValidateCtx(_ context.Context) error {
+	if err := k.checkAlgorithms(); err != nil {
+		return err
+	}
+
+	_, err := k.PrivateKey()
+	return err
+}
+
+// Prepare reads the key from the credential store.
+func (k *KeychainKey) Prepare(ctx context.Context) error {
+	return k.ValidateCtx(ctx)
+}
+
+// ReadyToSign checks the key is present in the credential store.
+func (k *KeychainKey) ReadyToSign(_ context.Context) (bool, error) {
+	k.mu.Lock()
+	loaded := k.privateKey != nil
+	k.mu.Unlock()
+
+	if signerProvider != nil || loaded {
+		return true, nil
+	}
+
+	_, err := keychainSecret(k.service, k.account)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (k *KeychainKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(k)
+}
+
+func (k *KeychainKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(k)
+}
+
+func (k *KeychainKey) ToConfig() config.AccountKey {
+	return config.AccountKey{
+		Type:            k.keyType,
+		Index:           k.index,
+		Weight:          k.weight,
+		WeightSet:       k.weightSet,
+		SigAlgo:         k.sigAlgo,
+		HashAlgo:        k.hashAlgo,
+		KeychainService: k.service,
+		KeychainAccount: k.account,
+	}
+}
+
+func (k *KeychainKey) String() string {
+	return fmt.Sprintf("KeychainKey{%s, service:%s, account:%s}", k.fields(), k.service, k.account)
+}
+
+// keychainSecret reads the secret for the service and account from the OS credential store.
+var keychainSecret = func(service string, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return "", fmt.Errorf("keychain keys are not supported on %s", runtime.GOOS)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("could not read the keychain entry %s/%s using %s: %w", service, account, cmd.Path, err)
+	}
+
+	secret := strings.TrimSpace(string(output))
+	if secret == "" {
+		return "", fmt.Errorf("keychain entry %s/%s not found", service, account)
+	}
+
+	return secret, nil
+}
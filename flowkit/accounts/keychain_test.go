@@ -0,0 +1,95 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+func Test_KeychainKey(t *testing.T) {
+	const privateKey = "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+
+	original := keychainSecret
+	defer func() { keychainSecret = original }()
+
+	reads := 0
+	keychainSecret = func(service string, account string) (string, error) {
+		reads++
+		if service == "flow" && account == "alice" {
+			return privateKey, nil
+		}
+		return "", fmt.Errorf("keychain entry %s/%s not found", service, account)
+	}
+
+	confKey := config.AccountKey{
+		Type:            config.KeyTypeKeychain,
+		SigAlgo:         crypto.ECDSA_P256,
+		HashAlgo:        crypto.SHA3_256,
+		KeychainService: "flow",
+		KeychainAccount: "alice",
+	}
+
+	key, err := keyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.Equal(t, confKey, key.ToConfig())
+	assert.Equal(t, "KeychainKey{index:0, sigAlgo:ECDSA_P256, hashAlgo:SHA3_256, service:flow, account:alice}", fmt.Sprint(key))
+
+	assert.NoError(t, key.Prepare(context.Background()))
+	pkey, err := key.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x"+privateKey, (*pkey).String())
+
+	_, err = key.Signer(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reads) // cached after the first read
+
+	// the entry is read once even if signers are created concurrently
+	reads = 0
+	key, err = keyFromConfig(confKey)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := key.Signer(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, reads)
+
+	confKey.KeychainAccount = "bob"
+	key, err = keyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.EqualError(t, key.Validate(), "keychain entry flow/bob not found")
+
+	confKey.KeychainAccount = ""
+	_, err = keyFromConfig(confKey)
+	assert.EqualError(t, err, "missing service or account for the keychain key")
+}
@@ -20,18 +20,29 @@ package accounts
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
+	"runtime"
 	"strings"
+	"sync"
 
 	goeth "github.com/ethereum/go-ethereum/accounts"
 	"github.com/lmars/go-slip10"
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/crypto"
 	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
 	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/exp/slices"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/sync/errgroup"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
 
 	"github.com/onflow/flow-cli/flowkit/config"
 )
@@ -45,11 +56,17 @@ type PublicKey struct {
 }
 
 // Key defines functions any key representation must implement.
+//
+// The metadata methods Type, Index, Weight, SigAlgo, HashAlgo and ToConfig never load the private key
+// or reach remote services, so keys can be listed without reading key files, decrypting or touching KMS.
 type Key interface {
 	// Type returns the key type (hex, kms, file...)
 	Type() config.KeyType
-	// Index returns the key index on the account
+	// Index returns the key index on the account, it must match the index at which the key
+	// is registered on the network since signatures are attached using it
 	Index() int
+	// Weight returns the key weight on the account, defaults to full weight
+	Weight() int
 	// SigAlgo returns signature algorithm used for signing
 	SigAlgo() crypto.SignatureAlgorithm
 	// HashAlgo returns hash algorithm used for signing
@@ -60,11 +77,65 @@ type Key interface {
 	ToConfig() config.AccountKey
 	// Validate key
 	Validate() error
+	// ValidateCtx validates the key same as Validate, the context allows cancelling
+	// or limiting the time of remote calls such as signing in to the KMS provider
+	ValidateCtx(ctx context.Context) error
+	// Prepare eagerly sets up the signer so the first signing doesn't pay the setup latency,
+	// calling it is optional but recommended before signing many transactions with remote keys
+	Prepare(ctx context.Context) error
+	// SupportsMessageSigning returns whether the key can sign arbitrary user messages,
+	// some remote backends can only sign transactions
+	SupportsMessageSigning() bool
+	// ReadyToSign cheaply checks whether everything needed to sign is available, such as credentials
+	// or the key file, without signing, the error explains why the key is not ready
+	ReadyToSign(ctx context.Context) (bool, error)
+	// ToFlowAccountKey converts the key to the account key format used when adding it to an account on the network
+	ToFlowAccountKey() (*flow.AccountKey, error)
+	// AddKeyArgs returns the Cadence arguments adding the key to an account on the network, which are
+	// the public key hex, the signature and hash algorithm raw values and the weight
+	AddKeyArgs() ([]cadence.Value, error)
 	// PrivateKey returns the private key if possible,
 	// depends on the key type
 	PrivateKey() (*crypto.PrivateKey, error)
 }
 
+// SignerProvider creates signers for keys.
+//
+// Setting a provider replaces the signing backend of every key type, which
+// allows tests to use fake signers instead of real cryptography or remote services.
+type SignerProvider interface {
+	Signer(ctx context.Context, key Key) (crypto.Signer, error)
+}
+
+var signerProvider SignerProvider
+
+// SetSignerProvider sets the provider used by all keys to create signers,
+// setting it to nil restores the default signers.
+func SetSignerProvider(provider SignerProvider) {
+	signerProvider = provider
+}
+
+// SetDefaultAlgorithms sets the algorithms used by keys that don't specify them,
+// which allows a project to use for example secp256k1 and SHA2_256 instead of P256 and SHA3_256.
+//
+// Advanced keys loaded from the configuration file without algorithms get the defaults as well,
+// so the defaults must be set before loading the configuration.
+func SetDefaultAlgorithms(sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) {
+	config.SetDefaultAlgorithms(sigAlgo, hashAlgo)
+}
+
+var strictAlgorithms bool
+
+// SetStrictAlgorithms enables the strict mode in which keys without a signature or hash algorithm fail
+// to validate and create signers, instead of using the default algorithms. This catches configurations
+// where the algorithm failed to parse, which otherwise silently results in signing with a different algorithm.
+//
+// Keys loaded from the configuration file always have both algorithms set, since simple format keys use
+// P256 and SHA3_256 and advanced keys without algorithms get the project defaults, so they pass strict mode.
+func SetStrictAlgorithms(strict bool) {
+	strictAlgorithms = strict
+}
+
 var _ Key = &HexKey{}
 
 var _ Key = &KMSKey{}
@@ -81,24 +152,42 @@ func keyFromConfig(accountKeyConf config.AccountKey) (Key, error) {
 		return kmsKeyFromConfig(accountKeyConf)
 	case config.KeyTypeFile:
 		return fileKeyFromConfig(accountKeyConf)
+	case config.KeyTypeKMS:
+		return remoteKMSKeyFromConfig(accountKeyConf)
+	case config.KeyTypeRemoteHTTP:
+		return remoteHTTPKeyFromConfig(accountKeyConf)
+	case config.KeyTypeKeychain:
+		return keychainKeyFromConfig(accountKeyConf)
+	case config.KeyTypeWebAuthn:
+		return webAuthnKeyFromConfig(accountKeyConf)
+	case config.KeyTypeURL:
+		return urlKeyFromConfig(accountKeyConf)
+	case config.KeyTypeCommand:
+		return commandKeyFromConfig(accountKeyConf)
+	case config.KeyTypeKeystore:
+		return keystoreKeyFromConfig(accountKeyConf)
 	}
 
 	return nil, fmt.Errorf(`invalid key type: "%s"`, accountKeyConf.Type)
 }
 
 type baseKey struct {
-	keyType  config.KeyType
-	index    int
-	sigAlgo  crypto.SignatureAlgorithm
-	hashAlgo crypto.HashAlgorithm
+	keyType   config.KeyType
+	index     int
+	weight    int
+	weightSet bool
+	sigAlgo   crypto.SignatureAlgorithm
+	hashAlgo  crypto.HashAlgorithm
 }
 
 func baseKeyFromConfig(accountKeyConf config.AccountKey) *baseKey {
 	return &baseKey{
-		keyType:  accountKeyConf.Type,
-		index:    accountKeyConf.Index,
-		sigAlgo:  accountKeyConf.SigAlgo,
-		hashAlgo: accountKeyConf.HashAlgo,
+		keyType:   accountKeyConf.Type,
+		index:     accountKeyConf.Index,
+		weight:    accountKeyConf.Weight,
+		weightSet: accountKeyConf.WeightSet,
+		sigAlgo:   accountKeyConf.SigAlgo,
+		hashAlgo:  accountKeyConf.HashAlgo,
 	}
 }
 
@@ -108,14 +197,16 @@ func (a *baseKey) Type() config.KeyType {
 
 func (a *baseKey) SigAlgo() crypto.SignatureAlgorithm {
 	if a.sigAlgo == crypto.UnknownSignatureAlgorithm {
-		return crypto.ECDSA_P256 // default value
+		sigAlgo, _ := config.DefaultAlgorithms()
+		return sigAlgo
 	}
 	return a.sigAlgo
 }
 
 func (a *baseKey) HashAlgo() crypto.HashAlgorithm {
 	if a.hashAlgo == crypto.UnknownHashAlgorithm {
-		return crypto.SHA3_256 // default value
+		_, hashAlgo := config.DefaultAlgorithms()
+		return hashAlgo
 	}
 	return a.hashAlgo
 }
@@ -124,66 +215,429 @@ func (a *baseKey) Index() int {
 	return a.index // default to 0
 }
 
+func (a *baseKey) Weight() int {
+	if a.weight == 0 && !a.weightSet {
+		return flow.AccountKeyWeightThreshold // default value
+	}
+	return a.weight
+}
+
 func (a *baseKey) Validate() error {
+	return a.checkAlgorithms()
+}
+
+func (a *baseKey) ValidateCtx(_ context.Context) error {
+	return a.checkAlgorithms()
+}
+
+// checkAlgorithms returns an error in strict mode if the key doesn't specify the signature or hash algorithm.
+func (a *baseKey) checkAlgorithms() error {
+	if !strictAlgorithms {
+		return nil
+	}
+	if a.sigAlgo == crypto.UnknownSignatureAlgorithm {
+		return fmt.Errorf("key at index %d doesn't specify a signature algorithm", a.index)
+	}
+	if a.hashAlgo == crypto.UnknownHashAlgorithm {
+		return fmt.Errorf("key at index %d doesn't specify a hash algorithm", a.index)
+	}
+	return nil
+}
+
+func (a *baseKey) Prepare(_ context.Context) error {
 	return nil
 }
 
+func (a *baseKey) SupportsMessageSigning() bool {
+	return true
+}
+
+// flowAccountKey builds the network account key from the key metadata and its public key.
+func flowAccountKey(key Key) (*flow.AccountKey, error) {
+	publicKey, err := keyPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	accountKey := &flow.AccountKey{
+		Index:     key.Index(),
+		PublicKey: publicKey,
+		SigAlgo:   key.SigAlgo(),
+		HashAlgo:  key.HashAlgo(),
+		Weight:    key.Weight(),
+	}
+
+	err = accountKey.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid account key: %w", err)
+	}
+
+	return accountKey, nil
+}
+
+// addKeyArgs converts the key to the arguments of transactions adding the key with the Cadence
+// PublicKey and addKey functions, the algorithms are passed as raw values of the Cadence enums.
+func addKeyArgs(key Key) ([]cadence.Value, error) {
+	accountKey, err := key.ToFlowAccountKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var sigAlgo sema.SignatureAlgorithm
+	switch accountKey.SigAlgo {
+	case crypto.ECDSA_P256:
+		sigAlgo = sema.SignatureAlgorithmECDSA_P256
+	case crypto.ECDSA_secp256k1:
+		sigAlgo = sema.SignatureAlgorithmECDSA_secp256k1
+	default:
+		return nil, fmt.Errorf("signature algorithm %s is not supported for account keys", accountKey.SigAlgo)
+	}
+
+	var hashAlgo sema.HashAlgorithm
+	switch accountKey.HashAlgo {
+	case crypto.SHA2_256:
+		hashAlgo = sema.HashAlgorithmSHA2_256
+	case crypto.SHA3_256:
+		hashAlgo = sema.HashAlgorithmSHA3_256
+	default:
+		return nil, fmt.Errorf("hash algorithm %s is not supported for account keys", accountKey.HashAlgo)
+	}
+
+	weight, err := cadence.NewUFix64(fmt.Sprintf("%d.0", accountKey.Weight))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key weight %d: %w", accountKey.Weight, err)
+	}
+
+	return []cadence.Value{
+		cadence.String(hex.EncodeToString(accountKey.PublicKey.Encode())),
+		cadence.NewUInt8(sigAlgo.RawValue()),
+		cadence.NewUInt8(hashAlgo.RawValue()),
+		weight,
+	}, nil
+}
+
+// SameKey returns whether both keys represent the same account key.
+//
+// Keys are the same if they have the same type, index, algorithms and public key. The public key
+// is taken from the signer for keys without an accessible private key, such as remote keys.
+func SameKey(a, b Key) (bool, error) {
+	if a.Type() != b.Type() ||
+		a.Index() != b.Index() ||
+		a.SigAlgo() != b.SigAlgo() ||
+		a.HashAlgo() != b.HashAlgo() {
+		return false, nil
+	}
+
+	publicA, err := keyPublicKey(a)
+	if err != nil {
+		return false, err
+	}
+
+	publicB, err := keyPublicKey(b)
+	if err != nil {
+		return false, err
+	}
+
+	return publicA.Equals(publicB), nil
+}
+
+// keyPublicKey returns the public key of the key, using the cached public key if available
+// and the signer if the private key is not accessible.
+func keyPublicKey(key Key) (crypto.PublicKey, error) {
+	return keyPublicKeyCtx(context.Background(), key)
+}
+
+// keyPublicKeyCtx returns the public key same as keyPublicKey, the context is used by the signer.
+func keyPublicKeyCtx(ctx context.Context, key Key) (crypto.PublicKey, error) {
+	if cached, ok := key.(interface{ cachedPublicKey() crypto.PublicKey }); ok {
+		if publicKey := cached.cachedPublicKey(); publicKey != nil {
+			return publicKey, nil
+		}
+	}
+
+	if pkey, err := key.PrivateKey(); err == nil {
+		return (*pkey).PublicKey(), nil
+	}
+
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.PublicKey(), nil
+}
+
+// fields returns the key metadata formatted for printing, it must never include secrets.
+func (a *baseKey) fields() string {
+	return fmt.Sprintf("index:%d, sigAlgo:%s, hashAlgo:%s", a.Index(), a.SigAlgo(), a.HashAlgo())
+}
+
 // KMSKey implements Gcloud KMS system for signing.
 type KMSKey struct {
 	*baseKey
-	kmsKey cloudkms.Key
+	kmsKey        cloudkms.Key
+	signer        crypto.Signer
+	gcloudAccount string
+	// algoChecked is set once the KMS key algorithm was confirmed to be supported by Flow.
+	algoChecked bool
 }
 
 // ToConfig convert account key to configuration.
 func (a *KMSKey) ToConfig() config.AccountKey {
 	return config.AccountKey{
-		Type:       a.keyType,
-		Index:      a.index,
-		SigAlgo:    a.sigAlgo,
-		HashAlgo:   a.hashAlgo,
-		ResourceID: a.kmsKey.ResourceID(),
+		Type:          a.keyType,
+		Index:         a.index,
+		Weight:        a.weight,
+		WeightSet:     a.weightSet,
+		SigAlgo:       a.sigAlgo,
+		HashAlgo:      a.hashAlgo,
+		ResourceID:    a.kmsKey.ResourceID(),
+		GcloudAccount: a.gcloudAccount,
 	}
 }
 
 func (a *KMSKey) Signer(ctx context.Context) (crypto.Signer, error) {
-	kmsClient, err := cloudkms.NewClient(ctx)
+	if err := a.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, a)
+	}
+
+	if a.signer != nil { // signer was already prepared
+		return a.signer, nil
+	}
+
+	kmsClient, err := newKMSClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	accountKMSSigner, err := kmsClient.SignerForKey(
-		ctx,
+		withCorrelationMetadata(ctx),
 		a.kmsKey,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return accountKMSSigner, nil
+	return canonicalSigner(a.SigAlgo(), accountKMSSigner), nil
 }
 
+// Validate makes sure Google credentials are available, signing in with gcloud only
+// if application default credentials can not be found.
 func (a *KMSKey) Validate() error {
-	return gcloudApplicationSignin(a.kmsKey.ResourceID())
+	return a.ValidateCtx(context.Background())
+}
+
+// ValidateCtx validates the key same as Validate, cancelling the context stops the gcloud sign-in.
+func (a *KMSKey) ValidateCtx(ctx context.Context) error {
+	if err := a.checkAlgorithms(); err != nil {
+		return err
+	}
+
+	if kmsClientFactory == nil && !hasApplicationDefaultCredentials() {
+		err := gcloudApplicationSignin(ctx, a.kmsKey, a.gcloudAccount)
+		if err != nil {
+			return err
+		}
+	}
+
+	// keys signed by a provider never reach KMS
+	if a.algoChecked || signerProvider != nil {
+		return nil
+	}
+
+	algo, err := kmsKeyAlgorithm(ctx, a.kmsKey)
+	if err != nil {
+		return err
+	}
+
+	err = checkKMSAlgorithm(algo)
+	if err != nil {
+		return err
+	}
+
+	a.algoChecked = true
+	return nil
+}
+
+// ReadyToSign checks the signer was prepared, a custom KMS client is set or the application default credentials are available.
+func (a *KMSKey) ReadyToSign(_ context.Context) (bool, error) {
+	if signerProvider != nil || kmsClientFactory != nil || a.signer != nil || hasApplicationDefaultCredentials() {
+		return true, nil
+	}
+	return false, fmt.Errorf("no Google application default credentials found, sign in with gcloud auth application-default login")
+}
+
+// kmsKeyAlgorithm fetches the algorithm of the KMS key version.
+var kmsKeyAlgorithm = func(ctx context.Context, key cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+	client, err := newKMSClient(ctx)
+	if err != nil {
+		return kmspb.CryptoKeyVersion_CRYPTO_KEY_VERSION_ALGORITHM_UNSPECIFIED, err
+	}
+	defer client.Close()
+
+	return client.KeyAlgorithm(ctx, key)
+}
+
+// checkKMSAlgorithm makes sure the KMS key algorithm is one Flow accepts,
+// otherwise every transaction signed by the key would be rejected by the network.
+func checkKMSAlgorithm(algo kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm) error {
+	switch algo {
+	case kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256, kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256:
+		return nil
+	}
+
+	family := algo.String()
+	switch {
+	case strings.HasPrefix(family, "RSA_"):
+		family = "RSA"
+	case strings.HasPrefix(family, "EC_SIGN_"):
+		family = strings.TrimPrefix(family, "EC_SIGN_")
+	}
+
+	return fmt.Errorf("KMS key uses %s which Flow does not support; use ECDSA P-256 or secp256k1", family)
+}
+
+// Prepare signs in with gcloud and creates the KMS client and signer which are reused for all following signing.
+//
+// The provided context is used by the prepared signer for all the signing requests.
+func (a *KMSKey) Prepare(ctx context.Context) error {
+	err := a.ValidateCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	signer, err := a.Signer(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.signer = signer
+	return nil
+}
+
+// RefreshMetadata fetches the public key metadata from KMS and updates the key algorithms,
+// if the signer was prepared it is recreated so it uses the current public key.
+//
+// Long-running services should call it after the key is changed in KMS, or periodically (e.g. hourly)
+// if changes are not announced. Each call makes a request to KMS so it shouldn't be called before every signing.
+func (a *KMSKey) RefreshMetadata(ctx context.Context) error {
+	publicKey, hashAlgo, err := kmsPublicKey(ctx, a.kmsKey)
+	if err != nil {
+		return err
+	}
+
+	a.sigAlgo = publicKey.Algorithm()
+	a.hashAlgo = hashAlgo
+
+	if a.signer != nil {
+		a.signer = nil
+		return a.Prepare(ctx)
+	}
+
+	return nil
+}
+
+// kmsPublicKey fetches the public key and the hash algorithm of the KMS key.
+var kmsPublicKey = func(ctx context.Context, key cloudkms.Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+	client, err := newKMSClient(ctx)
+	if err != nil {
+		return nil, crypto.UnknownHashAlgorithm, err
+	}
+	defer client.Close()
+
+	return client.GetPublicKey(ctx, key)
+}
+
+// kmsBatchConcurrency is the maximum number of signing requests sent to KMS at the same time.
+const kmsBatchConcurrency = 8
+
+// SignBatch signs all the messages using a single KMS client.
+//
+// Messages are signed concurrently, bounded to stay within KMS request quotas,
+// and the signatures are returned in the same order as the messages.
+func (a *KMSKey) SignBatch(ctx context.Context, messages [][]byte) ([][]byte, error) {
+	signer, err := a.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	signatures := make([][]byte, len(messages))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(kmsBatchConcurrency)
+
+	for i, message := range messages {
+		i, message := i, message
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			signature, err := signer.Sign(message)
+			if err != nil {
+				return fmt.Errorf("failed to sign message %d: %w", i, err)
+			}
+
+			signatures[i] = signature
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return signatures, nil
+}
+
+func (a *KMSKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(a)
+}
+
+func (a *KMSKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(a)
 }
 
 func (a *KMSKey) PrivateKey() (*crypto.PrivateKey, error) {
 	return nil, fmt.Errorf("private key not accessible")
 }
 
+// ResourceKey returns the KMS key handle, which can be used to query the key in KMS directly.
+func (a *KMSKey) ResourceKey() cloudkms.Key {
+	return a.kmsKey
+}
+
+func (a *KMSKey) String() string {
+	return fmt.Sprintf("KMSKey{%s, resourceID:%s}", a.fields(), a.kmsKey.ResourceID())
+}
+
+// GcloudAccountEnv is the environment variable which sets the gcloud account used to sign in,
+// if the account is not set in the key configuration.
+const GcloudAccountEnv = "FLOW_GCLOUD_ACCOUNT"
+
+// hasApplicationDefaultCredentials checks whether Google application default credentials can be discovered
+// from the environment, the gcloud well-known file or the metadata server (e.g. workload identity).
+var hasApplicationDefaultCredentials = func() bool {
+	_, err := google.FindDefaultCredentials(context.Background(), kmsScope)
+	return err == nil
+}
+
+const kmsScope = "https://www.googleapis.com/auth/cloudkms"
+
 // gcloudApplicationSignin signs in as an application user using gcloud command line tool
 // currently assumes gcloud is already installed on the machine
 // will by default pop a browser window to sign in
-func gcloudApplicationSignin(resourceID string) error {
+//
+// If the account is provided, or set in the environment, gcloud signs in with that account.
+func gcloudApplicationSignin(ctx context.Context, kms cloudkms.Key, account string) error {
 	googleAppCreds := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
 	if len(googleAppCreds) > 0 {
 		return nil
 	}
 
-	kms, err := cloudkms.KeyFromResourceID(resourceID)
-	if err != nil {
-		return err
-	}
-
 	proj := kms.ProjectID
 	if len(proj) == 0 {
 		return fmt.Errorf(
@@ -191,10 +645,17 @@ func gcloudApplicationSignin(resourceID string) error {
 		)
 	}
 
-	loginCmd := exec.Command("gcloud", "auth", "application-default", "login", fmt.Sprintf("--project=%s", proj))
+	if account == "" {
+		account = os.Getenv(GcloudAccountEnv)
+	}
+
+	loginCmd := exec.CommandContext(ctx, "gcloud", gcloudLoginArgs(proj, account)...)
 
 	output, err := loginCmd.CombinedOutput()
 	if err != nil {
+		if account != "" {
+			return fmt.Errorf("Failed to run %q as %s: %s\n", loginCmd.String(), account, err)
+		}
 		return fmt.Errorf("Failed to run %q: %s\n", loginCmd.String(), err)
 	}
 
@@ -208,6 +669,15 @@ func gcloudApplicationSignin(resourceID string) error {
 	return nil
 }
 
+// gcloudLoginArgs returns the gcloud arguments to sign in to the project with the optional account.
+func gcloudLoginArgs(project string, account string) []string {
+	args := []string{"auth", "application-default", "login", fmt.Sprintf("--project=%s", project)}
+	if account != "" {
+		args = append(args, fmt.Sprintf("--account=%s", account))
+	}
+	return args
+}
+
 func kmsKeyFromConfig(key config.AccountKey) (Key, error) {
 	accountKMSKey, err := cloudkms.KeyFromResourceID(key.ResourceID)
 	if err != nil {
@@ -215,20 +685,19 @@ func kmsKeyFromConfig(key config.AccountKey) (Key, error) {
 	}
 
 	return &KMSKey{
-		baseKey: &baseKey{
-			keyType:  config.KeyTypeGoogleKMS,
-			index:    key.Index,
-			sigAlgo:  key.SigAlgo,
-			hashAlgo: key.HashAlgo,
-		},
-		kmsKey: accountKMSKey,
+		baseKey:       baseKeyFromConfig(key),
+		kmsKey:        accountKMSKey,
+		gcloudAccount: key.GcloudAccount,
 	}, nil
 }
 
 // HexKey implements account key in hex representation.
+//
+// The private key can be stored encrypted in the configuration, in which case it is decrypted on first use.
 type HexKey struct {
 	*baseKey
 	privateKey crypto.PrivateKey
+	encrypted  *config.EncryptedSecret
 }
 
 func NewHexKeyFromPrivateKey(
@@ -247,25 +716,112 @@ func NewHexKeyFromPrivateKey(
 	}
 }
 
+// GenerateBackedUpHexKey generates a hex key derived from a new mnemonic using the default derivation path.
+//
+// The mnemonic is only returned to the caller so it can be shown to the user as a backup,
+// the key itself is a plain hex key which doesn't store the mnemonic in the configuration.
+func GenerateBackedUpHexKey(
+	sigAlgo crypto.SignatureAlgorithm,
+	hashAlgo crypto.HashAlgorithm,
+) (*HexKey, string, error) {
+	entropy, err := bip39.NewEntropy(128)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate mnemonic: %w", err)
+	}
+
+	bip44Key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        sigAlgo,
+		HashAlgo:       hashAlgo,
+		Mnemonic:       mnemonic,
+		DerivationPath: defaultDerivationPath,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	pkey, err := bip44Key.PrivateKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return NewHexKeyFromPrivateKey(0, hashAlgo, *pkey), mnemonic, nil
+}
+
 func hexKeyFromConfig(accountKey config.AccountKey) (*HexKey, error) {
 	return &HexKey{
 		baseKey:    baseKeyFromConfig(accountKey),
 		privateKey: accountKey.PrivateKey,
+		encrypted:  accountKey.Encrypted,
 	}, nil
 }
 
 func (a *HexKey) Signer(ctx context.Context) (crypto.Signer, error) {
-	return crypto.NewInMemorySigner(a.privateKey, a.HashAlgo())
+	if err := a.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, a)
+	}
+
+	key, err := a.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemorySigner(*key, a.HashAlgo())
+}
+
+func (a *HexKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(a)
+}
+
+func (a *HexKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(a)
 }
 
 func (a *HexKey) PrivateKey() (*crypto.PrivateKey, error) {
+	if a.privateKey == nil && a.encrypted != nil { // lazy decrypt
+		secret, err := decryptSecret(a.encrypted)
+		if err != nil {
+			return nil, err
+		}
+		pkey, err := config.DecodePrivateKeyHex(a.SigAlgo(), string(secret))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode the encrypted key: %w", err)
+		}
+		a.privateKey = pkey
+	}
+	if a.privateKey == nil {
+		return nil, fmt.Errorf("no private key configured for account")
+	}
 	return &a.privateKey, nil
 }
 
 func (a *HexKey) ToConfig() config.AccountKey {
+	if a.encrypted != nil { // never store the decrypted key
+		return config.AccountKey{
+			Type:      a.keyType,
+			Index:     a.index,
+			Weight:    a.weight,
+			WeightSet: a.weightSet,
+			SigAlgo:   a.sigAlgo,
+			HashAlgo:  a.hashAlgo,
+			Encrypted: a.encrypted,
+		}
+	}
+
 	return config.AccountKey{
 		Type:       a.keyType,
 		Index:      a.index,
+		Weight:     a.weight,
+		WeightSet:  a.weightSet,
 		SigAlgo:    a.sigAlgo,
 		HashAlgo:   a.hashAlgo,
 		PrivateKey: a.privateKey,
@@ -273,16 +829,72 @@ func (a *HexKey) ToConfig() config.AccountKey {
 }
 
 func (a *HexKey) Validate() error {
-	_, err := crypto.DecodePrivateKeyHex(a.sigAlgo, a.privateKeyHex())
+	return a.ValidateCtx(context.Background())
+}
+
+func (a *HexKey) ValidateCtx(_ context.Context) error {
+	if err := a.checkAlgorithms(); err != nil {
+		return err
+	}
+
+	_, err := a.PrivateKey()
+	if err != nil {
+		return err
+	}
+
+	_, err = crypto.DecodePrivateKeyHex(a.SigAlgo(), a.privateKeyHex())
 	if err != nil {
 		return fmt.Errorf("invalid private key: %w", err)
 	}
 
+	// an unknown hash algorithm uses the default, any other must be usable with the signature algorithm
+	if !crypto.CompatibleAlgorithms(a.SigAlgo(), a.HashAlgo()) {
+		return fmt.Errorf("invalid hash algorithm %s for signature algorithm %s", a.HashAlgo(), a.SigAlgo())
+	}
+
 	return nil
 }
 
-func (a *HexKey) privateKeyHex() string {
-	return hex.EncodeToString(a.privateKey.Encode())
+// ReadyToSign checks the private key is set or the passphrase to decrypt it is available.
+func (a *HexKey) ReadyToSign(_ context.Context) (bool, error) {
+	if signerProvider != nil || a.privateKey != nil {
+		return true, nil
+	}
+	if a.encrypted != nil {
+		return passphraseAvailable()
+	}
+	return false, fmt.Errorf("no private key configured for account")
+}
+
+func (a *HexKey) String() string {
+	return fmt.Sprintf("HexKey{%s}", a.fields())
+}
+
+func (a *HexKey) privateKeyHex() string {
+	return hex.EncodeToString(a.privateKey.Encode())
+}
+
+// ToFile writes the private key hex encoded to the file at the location and returns a file key using it.
+//
+// The file is only readable by the owner, an encrypted key is decrypted before it's written.
+func (a *HexKey) ToFile(location string) (*FileKey, error) {
+	pkey, err := a.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(location, []byte(hex.EncodeToString((*pkey).Encode())), 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not write the key to the location %s: %w", location, err)
+	}
+
+	base := *a.baseKey
+	base.keyType = config.KeyTypeFile
+	return &FileKey{
+		baseKey:    &base,
+		privateKey: *pkey,
+		location:   location,
+	}, nil
 }
 
 // fileKeyFromConfig creates a hex account key from a file location
@@ -313,31 +925,89 @@ func NewFileKey(
 	}
 }
 
+var checkKeyFilePermissions bool
+
+// SetCheckKeyFilePermissions enables checking that key files are not accessible by other users when a file key
+// is loaded, similar to SSH refusing private keys with broad permissions. Broader permissions than 0600 are reported
+// as a configuration warning, or an error in the configuration strict mode. Permissions are not checked on Windows.
+func SetCheckKeyFilePermissions(enabled bool) {
+	checkKeyFilePermissions = enabled
+}
+
+// keyFilePermissionsError reports key file permissions broader than 0600 if the check is enabled.
+func keyFilePermissionsError(location string) error {
+	if !checkKeyFilePermissions || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := os.Stat(location)
+	if err != nil {
+		return fmt.Errorf("could not load the key for the account from provided location %s: %w", location, err)
+	}
+
+	perm := info.Mode().Perm()
+	if perm&0077 == 0 {
+		return nil
+	}
+
+	return config.WarnStrict(fmt.Sprintf(
+		"key file %s has permissions %04o which allow other users to access it, restrict them with chmod 600 %s",
+		location,
+		perm,
+		location,
+	))
+}
+
 // FileKey represents a key that is saved in a seperate file and will be lazy-loaded.
 //
 // The FileKey stores location of the file where private key is stored in hex-encoded format.
+// Loading the key is safe for concurrent use, so signers can be created from the same key in parallel.
 type FileKey struct {
 	*baseKey
+	mu         sync.Mutex
 	privateKey crypto.PrivateKey
 	location   string
 }
 
 func (f *FileKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := f.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, f)
+	}
+
 	key, err := f.PrivateKey()
 	if err != nil {
 		return nil, err
 	}
 
-	return crypto.NewInMemorySigner(*key, f.HashAlgo())
+	return newInMemorySigner(*key, f.HashAlgo())
+}
+
+func (f *FileKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(f)
+}
+
+func (f *FileKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(f)
 }
 
 func (f *FileKey) PrivateKey() (*crypto.PrivateKey, error) {
+	f.mu.Lock() // the file is only read once even if signers are created concurrently
+	defer f.mu.Unlock()
+
 	if f.privateKey == nil { // lazy load the key
+		if err := keyFilePermissionsError(f.location); err != nil {
+			return nil, err
+		}
+
 		key, err := os.ReadFile(f.location) // TODO(sideninja) change to use the state ReaderWriter
 		if err != nil {
 			return nil, fmt.Errorf("could not load the key for the account from provided location %s: %w", f.location, err)
 		}
-		pkey, err := crypto.DecodePrivateKeyHex(f.sigAlgo, strings.TrimPrefix(string(key), "0x"))
+		pkey, err := config.DecodePrivateKeyHex(f.SigAlgo(), string(key))
 		if err != nil {
 			return nil, fmt.Errorf("could not decode the key from provided location %s: %w", f.location, err)
 		}
@@ -346,48 +1016,170 @@ func (f *FileKey) PrivateKey() (*crypto.PrivateKey, error) {
 	return &f.privateKey, nil
 }
 
+// Prepare loads the key from the file.
+func (f *FileKey) Prepare(_ context.Context) error {
+	_, err := f.PrivateKey()
+	return err
+}
+
+// ReadyToSign checks the key was loaded or the key file is readable.
+func (f *FileKey) ReadyToSign(_ context.Context) (bool, error) {
+	f.mu.Lock()
+	loaded := f.privateKey != nil
+	f.mu.Unlock()
+
+	if signerProvider != nil || loaded {
+		return true, nil
+	}
+
+	file, err := os.Open(f.location)
+	if err != nil {
+		return false, fmt.Errorf("could not read the key file %s: %w", f.location, err)
+	}
+	_ = file.Close()
+
+	return true, nil
+}
+
+func (f *FileKey) String() string {
+	return fmt.Sprintf("FileKey{%s, location:%s}", f.fields(), f.location)
+}
+
 func (f *FileKey) ToConfig() config.AccountKey {
 	return config.AccountKey{
-		Type:     config.KeyTypeFile,
-		SigAlgo:  f.sigAlgo,
-		HashAlgo: f.hashAlgo,
-		Location: f.location,
+		Type:      config.KeyTypeFile,
+		Index:     f.index,
+		Weight:    f.weight,
+		WeightSet: f.weightSet,
+		SigAlgo:   f.sigAlgo,
+		HashAlgo:  f.hashAlgo,
+		Location:  f.location,
 	}
 }
 
+// ToHex loads the key from the file and returns a hex key which stores the key inline in the configuration.
+func (f *FileKey) ToHex() (*HexKey, error) {
+	pkey, err := f.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	base := *f.baseKey
+	base.keyType = config.KeyTypeHex
+	return &HexKey{
+		baseKey:    &base,
+		privateKey: *pkey,
+	}, nil
+}
+
 // BIP44Key implements https://github.com/onflow/flow/blob/master/flips/20201125-bip-44-multi-account.md
+//
+// The mnemonic can be stored encrypted in the configuration, in which case it is decrypted on first use.
+// If the mnemonic is not in the configuration at all it's obtained from the secret prompt on first use
+// and only kept in memory.
+// If a public key cache is set the derived public key is cached and the private key is only derived when needed.
 type BIP44Key struct {
 	*baseKey
+	mu             sync.Mutex
 	privateKey     crypto.PrivateKey
+	publicKey      crypto.PublicKey
 	mnemonic       string
+	prompted       bool
 	derivationPath string
+	path           goeth.DerivationPath
+	curve          config.Curve
+	encrypted      *config.EncryptedSecret
+	// derivations configures keys derived from the mnemonic, which are loaded as separate accounts
+	derivations []config.KeyDerivation
+	// derived is set for keys loaded from the derivations of another key, which aren't saved separately
+	derived bool
 }
 
+// defaultDerivationPath is the standard Flow derivation path of the first key.
+const defaultDerivationPath = "m/44'/539'/0'/0/0"
+
+// bip44KeyFromConfig creates the key from the configuration, if the derivation path
+// is not provided the default "m/44'/539'/0'/0/0" is used.
+//
+// The derivation path is parsed once when the key is created, so an invalid path fails here.
 func bip44KeyFromConfig(key config.AccountKey) (Key, error) {
+	derivationPath := normalizeDerivationPath(key.DerivationPath)
+	if derivationPath == "" {
+		derivationPath = defaultDerivationPath
+	}
+
+	path, err := goeth.ParseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %s: %w", derivationPath, err)
+	}
+
 	return &BIP44Key{
 		baseKey: &baseKey{
-			keyType:  config.KeyTypeBip44,
-			index:    key.Index,
-			sigAlgo:  key.SigAlgo,
-			hashAlgo: key.HashAlgo,
+			keyType:   config.KeyTypeBip44,
+			index:     key.Index,
+			weight:    key.Weight,
+			weightSet: key.WeightSet,
+			sigAlgo:   key.SigAlgo,
+			hashAlgo:  key.HashAlgo,
 		},
-		derivationPath: key.DerivationPath,
+		derivationPath: derivationPath,
+		path:           path,
+		curve:          key.Curve,
 		mnemonic:       key.Mnemonic,
+		encrypted:      key.Encrypted,
+		derivations:    key.Derivations,
 	}, nil
 }
 
+// parsedPath returns the parsed derivation path, parsing it only the first time.
+func (a *BIP44Key) parsedPath() (goeth.DerivationPath, error) {
+	if a.path == nil {
+		path, err := goeth.ParseDerivationPath(a.derivationPath)
+		if err != nil {
+			return nil, err
+		}
+		a.path = path
+	}
+	return a.path, nil
+}
+
 func (a *BIP44Key) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := a.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, a)
+	}
+
 	pkey, err := a.PrivateKey()
 	if err != nil {
 		return nil, err
 	}
 
-	return crypto.NewInMemorySigner(*pkey, a.HashAlgo())
+	return newInMemorySigner(*pkey, a.HashAlgo())
+}
+
+func (a *BIP44Key) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(a)
+}
+
+func (a *BIP44Key) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(a)
 }
 
 func (a *BIP44Key) PrivateKey() (*crypto.PrivateKey, error) {
+	a.mu.Lock() // the mnemonic is only decrypted or prompted once even if signers are created concurrently
+	defer a.mu.Unlock()
+
 	if a.privateKey == nil { // lazy load
-		err := a.Validate()
+		err := a.validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if a.privateKey == nil { // validated from the public key cache
+		err := a.derive()
 		if err != nil {
 			return nil, err
 		}
@@ -395,35 +1187,210 @@ func (a *BIP44Key) PrivateKey() (*crypto.PrivateKey, error) {
 	return &a.privateKey, nil
 }
 
+// cachedPublicKey returns the public key without deriving the private key if it was found in the cache.
+func (a *BIP44Key) cachedPublicKey() crypto.PublicKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.publicKey
+}
+
+// loadedMnemonic returns the mnemonic, which is empty if it's encrypted or prompted and wasn't used yet.
+func (a *BIP44Key) loadedMnemonic() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mnemonic
+}
+
+// ToConfig converts the key to configuration, the signature algorithm is always set
+// since it also determines the curve used for the key derivation.
+//
+// A decrypted or prompted mnemonic and the key derived from it are never part of the configuration.
 func (a *BIP44Key) ToConfig() config.AccountKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.encrypted != nil || a.prompted { // never store the decrypted or prompted mnemonic
+		return config.AccountKey{
+			Type:           a.keyType,
+			Index:          a.index,
+			Weight:         a.weight,
+			WeightSet:      a.weightSet,
+			SigAlgo:        a.SigAlgo(),
+			HashAlgo:       a.hashAlgo,
+			DerivationPath: a.derivationPath,
+			Curve:          a.curve,
+			Encrypted:      a.encrypted,
+			Derivations:    a.derivations,
+		}
+	}
+
 	return config.AccountKey{
 		Type:           a.keyType,
 		Index:          a.index,
-		SigAlgo:        a.sigAlgo,
+		Weight:         a.weight,
+		WeightSet:      a.weightSet,
+		SigAlgo:        a.SigAlgo(),
 		HashAlgo:       a.hashAlgo,
 		PrivateKey:     a.privateKey,
 		Mnemonic:       a.mnemonic,
 		DerivationPath: a.derivationPath,
+		Curve:          a.curve,
+		Derivations:    a.derivations,
+	}
+}
+
+// Prepare derives the key from the mnemonic.
+func (a *BIP44Key) Prepare(_ context.Context) error {
+	_, err := a.PrivateKey()
+	return err
+}
+
+// ReadyToSign checks the mnemonic is set or the passphrase to decrypt it is available,
+// or the secret prompt is set to obtain the mnemonic if it's not configured.
+func (a *BIP44Key) ReadyToSign(_ context.Context) (bool, error) {
+	a.mu.Lock()
+	loaded := a.privateKey != nil || a.mnemonic != ""
+	a.mu.Unlock()
+
+	if signerProvider != nil || loaded {
+		return true, nil
+	}
+	if a.encrypted != nil {
+		return passphraseAvailable()
+	}
+	if secretPrompt != nil {
+		return true, nil
 	}
+	return false, fmt.Errorf("no mnemonic configured for account")
+}
+
+func (a *BIP44Key) String() string {
+	return fmt.Sprintf("BIP44Key{%s, derivationPath:%s}", a.fields(), a.derivationPath)
 }
 
 func (a *BIP44Key) Validate() error {
+	return a.ValidateCtx(context.Background())
+}
+
+func (a *BIP44Key) ValidateCtx(_ context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.validate()
+}
+
+// validate decrypts or prompts the mnemonic if needed and derives the key, it must be called holding the lock.
+func (a *BIP44Key) validate() error {
+	if err := a.checkAlgorithms(); err != nil {
+		return err
+	}
 
-	if !bip39.IsMnemonicValid(a.mnemonic) {
+	if err := checkBIP44Curve(a.curve, a.SigAlgo()); err != nil {
+		return err
+	}
+
+	if a.mnemonic == "" && a.encrypted != nil { // lazy decrypt
+		secret, err := decryptSecret(a.encrypted)
+		if err != nil {
+			return err
+		}
+		a.mnemonic = string(secret)
+	}
+
+	if a.mnemonic == "" && a.encrypted == nil { // lazy prompt
+		if err := a.promptMnemonic(); err != nil {
+			return err
+		}
+	}
+
+	mnemonic := NormalizeMnemonic(a.mnemonic)
+	if !bip39.IsMnemonicValid(mnemonic) {
+		if a.prompted { // prompt again next time instead of keeping the mistyped mnemonic
+			a.mnemonic = ""
+			a.prompted = false
+			return fmt.Errorf("invalid mnemonic entered for account")
+		}
 		return fmt.Errorf("invalid mnemonic defined for account in flow.json")
 	}
 
-	derivationPath, err := goeth.ParseDerivationPath(a.derivationPath)
+	_, err := a.parsedPath()
 	if err != nil {
 		return fmt.Errorf("invalid derivation path defined for account in flow.json")
 	}
 
-	seed := bip39.NewSeed(a.mnemonic, "")
-	curve := slip10.CurveBitcoin
-	if a.sigAlgo == crypto.ECDSA_P256 {
-		curve = slip10.CurveP256
+	if publicKeyCache != nil {
+		a.publicKey = publicKeyCache.get(mnemonic, a.SigAlgo(), a.derivationPath)
+		if a.publicKey != nil {
+			return nil
+		}
+	}
+
+	err = a.derive()
+	if err != nil {
+		return err
+	}
+
+	if publicKeyCache != nil {
+		err = publicKeyCache.put(mnemonic, a.SigAlgo(), a.derivationPath, a.publicKey)
+		if err != nil {
+			config.Warn(fmt.Sprintf("failed to cache the derived public key: %s", err))
+		}
+	}
+
+	return nil
+}
+
+// promptMnemonic obtains the mnemonic which is not configured from the secret prompt,
+// the mnemonic is only kept in memory and never written back to the configuration.
+//
+// The lock is held while prompting, so concurrent signers wait for a single prompt.
+func (a *BIP44Key) promptMnemonic() error {
+	if secretPrompt == nil {
+		return fmt.Errorf("no mnemonic configured for account, set a secret prompt to enter it when signing")
+	}
+
+	mnemonic, err := secretPrompt(fmt.Sprintf("Mnemonic for the key at derivation path %s", a.derivationPath))
+	if err != nil {
+		return fmt.Errorf("failed to obtain the mnemonic: %w", err)
+	}
+
+	a.mnemonic = mnemonic
+	a.prompted = true
+	return nil
+}
+
+// VerifyDerivation checks the key derived from the mnemonic and derivation path matches the expected public key,
+// which catches a wrong mnemonic or derivation path when restoring a wallet before the key is used for signing.
+func (a *BIP44Key) VerifyDerivation(expected crypto.PublicKey) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.publicKey == nil {
+		if err := a.validate(); err != nil {
+			return err
+		}
+	}
+
+	if !a.publicKey.Equals(expected) {
+		return fmt.Errorf(
+			"key derived at path %s has public key %s, expected %s, check the mnemonic and the derivation path",
+			a.derivationPath,
+			a.publicKey,
+			expected,
+		)
+	}
+
+	return nil
+}
+
+// derive derives the private key from the validated mnemonic and derivation path, it must be called holding the lock.
+func (a *BIP44Key) derive() error {
+	derivationPath, err := a.parsedPath()
+	if err != nil {
+		return fmt.Errorf("invalid derivation path defined for account in flow.json")
 	}
-	accountKey, err := slip10.NewMasterKeyWithCurve(seed, curve)
+
+	seed := bip39.NewSeed(NormalizeMnemonic(a.mnemonic), "")
+	accountKey, err := bip44MasterKey(seed, a.curve, a.SigAlgo())
 	if err != nil {
 		return err
 	}
@@ -439,5 +1406,261 @@ func (a *BIP44Key) Validate() error {
 	if err != nil {
 		return err
 	}
+	a.publicKey = a.privateKey.PublicKey()
+	return nil
+}
+
+// DeriveKeyForName derives a key from the mnemonic for the name, so each named service gets
+// its own reproducible key without keeping track of the assigned indices.
+//
+// The address index is the first 4 bytes of the SHA-256 hash of the name, read as a big-endian
+// integer with the highest bit cleared, and the key is derived using the path m/44'/539'/0'/0/index.
+func DeriveKeyForName(
+	mnemonic string,
+	name string,
+	sigAlgo crypto.SignatureAlgorithm,
+	hashAlgo crypto.HashAlgorithm,
+) (*BIP44Key, error) {
+	key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        sigAlgo,
+		HashAlgo:       hashAlgo,
+		Mnemonic:       mnemonic,
+		DerivationPath: fmt.Sprintf("m/44'/539'/0'/0/%d", nameAddressIndex(name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = key.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return key.(*BIP44Key), nil
+}
+
+// BIP44Derivation describes a key derived from a mnemonic.
+type BIP44Derivation struct {
+	SigAlgo  crypto.SignatureAlgorithm
+	HashAlgo crypto.HashAlgorithm
+	// DerivationPath defaults to the derivation path of the key the keys are derived from.
+	DerivationPath string
+}
+
+// DeriveKeys derives a separate key from the mnemonic of the key for each derivation, so a single mnemonic
+// entry can back keys of different signature algorithms on the same or different accounts.
+//
+// The derived keys keep the index and the weight of the key, and an encrypted mnemonic stays encrypted
+// in the configuration of the derived keys.
+func (a *BIP44Key) DeriveKeys(derivations ...BIP44Derivation) ([]*BIP44Key, error) {
+	a.mu.Lock()
+	if a.mnemonic == "" { // decrypt or prompt the mnemonic once for all the derived keys
+		if err := a.validate(); err != nil {
+			a.mu.Unlock()
+			return nil, err
+		}
+	}
+	mnemonic, prompted := a.mnemonic, a.prompted
+	a.mu.Unlock()
+
+	keys := make([]*BIP44Key, 0, len(derivations))
+	for _, d := range derivations {
+		if !crypto.CompatibleAlgorithms(d.SigAlgo, d.HashAlgo) {
+			return nil, fmt.Errorf("invalid hash algorithm %s for signature algorithm %s", d.HashAlgo, d.SigAlgo)
+		}
+
+		derivationPath := d.DerivationPath
+		if derivationPath == "" {
+			derivationPath = a.derivationPath
+		}
+
+		key, err := bip44KeyFromConfig(config.AccountKey{
+			Type:           config.KeyTypeBip44,
+			Index:          a.index,
+			Weight:         a.weight,
+			WeightSet:      a.weightSet,
+			SigAlgo:        d.SigAlgo,
+			HashAlgo:       d.HashAlgo,
+			Mnemonic:       mnemonic,
+			DerivationPath: derivationPath,
+			Encrypted:      a.encrypted,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = key.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive the %s key: %w", d.SigAlgo, err)
+		}
+
+		derived := key.(*BIP44Key)
+		derived.prompted = prompted
+		keys = append(keys, derived)
+	}
+
+	return keys, nil
+}
+
+// derivedAccounts creates the accounts using the keys derived from the mnemonic of the key as configured
+// by the key derivations, the keys are only derived when they are used.
+//
+// The derived keys keep the weight of the key and use the address if the derivation doesn't set one.
+func (a *BIP44Key) derivedAccounts(address flow.Address) ([]Account, error) {
+	accounts := make([]Account, 0, len(a.derivations))
+	for _, d := range a.derivations {
+		derivationPath := d.DerivationPath
+		if derivationPath == "" {
+			derivationPath = a.derivationPath
+		}
+
+		key, err := bip44KeyFromConfig(config.AccountKey{
+			Type:           config.KeyTypeBip44,
+			Index:          d.Index,
+			Weight:         a.weight,
+			WeightSet:      a.weightSet,
+			SigAlgo:        d.SigAlgo,
+			HashAlgo:       d.HashAlgo,
+			Mnemonic:       a.mnemonic,
+			DerivationPath: derivationPath,
+			Encrypted:      a.encrypted,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid derived key %s: %w", d.Name, err)
+		}
+		key.(*BIP44Key).derived = true
+
+		derivedAddress := d.Address
+		if derivedAddress == flow.EmptyAddress {
+			derivedAddress = address
+		}
+
+		accounts = append(accounts, Account{
+			Name:    d.Name,
+			Address: derivedAddress,
+			Key:     key,
+		})
+	}
+
+	return accounts, nil
+}
+
+// nameAddressIndex maps the name to a non-hardened address index.
+func nameAddressIndex(name string) uint32 {
+	hash := sha256.Sum256([]byte(name))
+	return binary.BigEndian.Uint32(hash[:4]) & 0x7fffffff
+}
+
+// NormalizeMnemonic lowercases the mnemonic and collapses the whitespace between the words,
+// so mnemonics pasted with extra spaces, line breaks or capitalized words derive the same keys.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.ToLower(strings.Join(strings.Fields(mnemonic), " "))
+}
+
+// ValidateMnemonic checks the mnemonic is valid without deriving a key from it.
+//
+// The mnemonic is normalized before validation. The returned error describes the problem, whether
+// the number of words is wrong, a word is not in the word list, or the checksum doesn't match.
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	if !slices.Contains([]int{12, 15, 18, 21, 24}, len(words)) {
+		return fmt.Errorf("invalid mnemonic, expected 12, 15, 18, 21 or 24 words but got %d", len(words))
+	}
+
+	for i, word := range words {
+		if _, ok := bip39.GetWordIndex(strings.ToLower(word)); !ok {
+			return fmt.Errorf("invalid mnemonic, unknown word %q at position %d", word, i+1)
+		}
+	}
+
+	_, err := bip39.EntropyFromMnemonic(NormalizeMnemonic(mnemonic))
+	if err != nil {
+		return fmt.Errorf("invalid mnemonic, the checksum does not match, make sure the words are in the correct order")
+	}
+
+	return nil
+}
+
+// normalizeDerivationPath converts the derivation path written by the user to the canonical form.
+//
+// The leading "m" is optional, whitespace is ignored and hardened components can be marked with "h".
+// Without normalization a path missing the leading "m" is parsed relative to the Ethereum root path.
+func normalizeDerivationPath(path string) string {
+	path = strings.Join(strings.Fields(path), "")
+	if path == "" {
+		return path
+	}
+
+	components := strings.Split(strings.Trim(path, "/"), "/")
+	if strings.EqualFold(components[0], "m") {
+		components = components[1:]
+	}
+
+	for i, c := range components {
+		if strings.HasSuffix(c, "h") || strings.HasSuffix(c, "H") {
+			components[i] = c[:len(c)-1] + "'"
+		}
+	}
+
+	return "m/" + strings.Join(components, "/")
+}
+
+// bip44MasterKey creates the master key from the seed using the slip10 curve matching the signature algorithm,
+// or the configured curve if set, which must be consistent with the signature algorithm.
+//
+// Only ECDSA_P256 and ECDSA_secp256k1 keys can be derived, any other algorithm results in an error
+// instead of deriving the key on a wrong curve. The curve must never change for an algorithm
+// since it would result in different keys.
+//
+// SLIP-0010 ed25519 derivation is not provided since Flow accounts don't support ed25519 keys,
+// there is no ed25519 signature algorithm a derived key could be used with.
+func bip44MasterKey(seed []byte, curve config.Curve, sigAlgo crypto.SignatureAlgorithm) (*slip10.Key, error) {
+	if err := checkBIP44Curve(curve, sigAlgo); err != nil {
+		return nil, err
+	}
+
+	switch sigAlgo {
+	case crypto.ECDSA_P256:
+		return slip10.NewMasterKeyWithCurve(seed, slip10.CurveP256)
+	case crypto.ECDSA_secp256k1:
+		return slip10.NewMasterKeyWithCurve(seed, slip10.CurveBitcoin)
+	}
+
+	return nil, fmt.Errorf("signature algorithm %s is not supported for BIP44 keys", sigAlgo)
+}
+
+// bip44CurveSigAlgos maps the slip10 curves keys can be derived on to the signature algorithm using the curve.
+var bip44CurveSigAlgos = map[config.Curve]crypto.SignatureAlgorithm{
+	config.CurveNIST256P1: crypto.ECDSA_P256,
+	config.CurveSecp256k1: crypto.ECDSA_secp256k1,
+}
+
+// checkBIP44Curve checks the configured curve is supported by slip10 and doesn't contradict the signature algorithm,
+// since the derived key is used with the signature algorithm the curve can't differ from the algorithm's curve.
+func checkBIP44Curve(curve config.Curve, sigAlgo crypto.SignatureAlgorithm) error {
+	if curve == config.CurveInferred {
+		return nil
+	}
+
+	curveSigAlgo, ok := bip44CurveSigAlgos[curve]
+	if !ok {
+		return fmt.Errorf(
+			"curve %s is not supported for BIP44 keys, must be %s or %s",
+			curve,
+			config.CurveNIST256P1,
+			config.CurveSecp256k1,
+		)
+	}
+
+	if curveSigAlgo != sigAlgo {
+		return fmt.Errorf(
+			"curve %s contradicts signature algorithm %s of the BIP44 key, the curve requires %s",
+			curve,
+			sigAlgo,
+			curveSigAlgo,
+		)
+	}
+
 	return nil
 }
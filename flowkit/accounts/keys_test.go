@@ -19,12 +19,29 @@
 package accounts
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
+	flowcrypto "github.com/onflow/flow-go/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/tyler-smith/go-bip39"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
 
 	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/config/json"
 )
 
 func Test_KMS_Keys(t *testing.T) {
@@ -42,6 +59,286 @@ func Test_KMS_Keys(t *testing.T) {
 	_, err = kmsKey.PrivateKey()
 	assert.EqualError(t, err, "private key not accessible")
 	assert.Equal(t, confKey, kmsKey.ToConfig())
+
+	resourceKey := kmsKey.(*KMSKey).ResourceKey()
+	assert.Equal(t, "my-project", resourceKey.ProjectID)
+	assert.Equal(t, "flow", resourceKey.KeyRingID)
+	assert.Equal(t, "my-account", resourceKey.KeyID)
+	assert.Equal(t, "1", resourceKey.KeyVersion)
+	assert.Equal(t, confKey.ResourceID, resourceKey.ResourceID())
+
+	confKey.GcloudAccount = "ci@my-project.iam.gserviceaccount.com"
+	kmsKey, err = kmsKeyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.Equal(t, confKey, kmsKey.ToConfig())
+}
+
+func Test_KMS_Validate(t *testing.T) {
+	kmsKey, err := kmsKeyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeGoogleKMS,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+
+	original := hasApplicationDefaultCredentials
+	originalAlgorithm := kmsKeyAlgorithm
+	defer func() {
+		hasApplicationDefaultCredentials = original
+		kmsKeyAlgorithm = originalAlgorithm
+	}()
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("PATH", "") // make sure gcloud is never found
+
+	algoRequests := 0
+	kmsKeyAlgorithm = func(context.Context, cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+		algoRequests++
+		return kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256, nil
+	}
+
+	hasApplicationDefaultCredentials = func() bool { return true }
+	assert.NoError(t, kmsKey.Validate())
+	assert.NoError(t, kmsKey.Validate())
+	assert.Equal(t, 1, algoRequests)
+
+	hasApplicationDefaultCredentials = func() bool { return false }
+	assert.ErrorContains(t, kmsKey.Validate(), "gcloud")
+}
+
+func Test_KMS_ValidateCtx(t *testing.T) {
+	original := hasApplicationDefaultCredentials
+	originalAlgorithm := kmsKeyAlgorithm
+	defer func() {
+		hasApplicationDefaultCredentials = original
+		kmsKeyAlgorithm = originalAlgorithm
+	}()
+	hasApplicationDefaultCredentials = func() bool { return true }
+	kmsKeyAlgorithm = func(ctx context.Context, _ cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		return kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256, nil
+	}
+
+	key, err := keyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeGoogleKMS,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, key.ValidateCtx(ctx), context.Canceled)
+	assert.NoError(t, key.ValidateCtx(context.Background()))
+}
+
+func Test_KMS_ValidateAlgorithm(t *testing.T) {
+	original := hasApplicationDefaultCredentials
+	originalAlgorithm := kmsKeyAlgorithm
+	defer func() {
+		hasApplicationDefaultCredentials = original
+		kmsKeyAlgorithm = originalAlgorithm
+	}()
+	hasApplicationDefaultCredentials = func() bool { return true }
+
+	tests := []struct {
+		algo kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+		err  string
+	}{
+		{algo: kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256},
+		{algo: kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256},
+		{
+			algo: kmspb.CryptoKeyVersion_RSA_SIGN_PSS_2048_SHA256,
+			err:  "KMS key uses RSA which Flow does not support; use ECDSA P-256 or secp256k1",
+		},
+		{
+			algo: kmspb.CryptoKeyVersion_EC_SIGN_P384_SHA384,
+			err:  "KMS key uses P384_SHA384 which Flow does not support; use ECDSA P-256 or secp256k1",
+		},
+	}
+
+	for _, test := range tests {
+		algo := test.algo
+		kmsKeyAlgorithm = func(context.Context, cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+			return algo, nil
+		}
+
+		key, err := kmsKeyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeGoogleKMS,
+			ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+		})
+		assert.NoError(t, err)
+
+		err = key.Validate()
+		if test.err == "" {
+			assert.NoError(t, err, algo.String())
+		} else {
+			assert.EqualError(t, err, test.err, algo.String())
+		}
+	}
+}
+
+func Test_KMS_SignBatch(t *testing.T) {
+	key, err := kmsKeyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeGoogleKMS,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	publicKey := pkey.PublicKey()
+
+	signer, err := crypto.NewInMemorySigner(pkey, crypto.SHA3_256)
+	assert.NoError(t, err)
+	kmsKey := key.(*KMSKey)
+	kmsKey.signer = &syncSigner{signer: signer} // avoid connecting to KMS
+
+	messages := make([][]byte, 20)
+	for i := range messages {
+		messages[i] = []byte(fmt.Sprintf("message %d", i))
+	}
+
+	signatures, err := kmsKey.SignBatch(context.Background(), messages)
+	assert.NoError(t, err)
+	assert.Len(t, signatures, len(messages))
+
+	hasher, err := crypto.NewHasher(crypto.SHA3_256)
+	assert.NoError(t, err)
+	for i, signature := range signatures {
+		valid, err := publicKey.Verify(signature, messages[i], hasher)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = kmsKey.SignBatch(ctx, messages)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// syncSigner makes the in-memory signer safe for concurrent use like the KMS signer.
+type syncSigner struct {
+	mu     sync.Mutex
+	signer crypto.Signer
+}
+
+func (s *syncSigner) Sign(message []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.signer.Sign(message)
+}
+
+func (s *syncSigner) PublicKey() crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+func Test_HexKey_Missing(t *testing.T) {
+	key, err := keyFromConfig(config.AccountKey{
+		Type:     config.KeyTypeHex,
+		SigAlgo:  config.DefaultSigAlgo,
+		HashAlgo: config.DefaultHashAlgo,
+	})
+	assert.NoError(t, err)
+
+	_, err = key.PrivateKey()
+	assert.EqualError(t, err, "no private key configured for account")
+	assert.EqualError(t, key.Validate(), "no private key configured for account")
+
+	_, err = key.Signer(context.Background())
+	assert.EqualError(t, err, "no private key configured for account")
+}
+
+func Test_HexKey_HashAlgo(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	for _, hashAlgo := range []crypto.HashAlgorithm{crypto.UnknownHashAlgorithm, crypto.SHA3_256, crypto.SHA2_256, crypto.Keccak256} {
+		key := NewHexKeyFromPrivateKey(0, hashAlgo, pkey)
+		assert.NoError(t, key.Validate())
+	}
+
+	key := NewHexKeyFromPrivateKey(0, crypto.SHA3_384, pkey)
+	assert.EqualError(t, key.Validate(), "invalid hash algorithm SHA3_384 for signature algorithm ECDSA_P256")
+}
+
+func Test_GenerateBackedUpHexKey(t *testing.T) {
+	key, mnemonic, err := GenerateBackedUpHexKey(crypto.ECDSA_secp256k1, crypto.SHA2_256)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 12)
+	assert.Equal(t, config.KeyTypeHex, key.Type())
+	assert.Equal(t, crypto.ECDSA_secp256k1, key.SigAlgo())
+	assert.Equal(t, crypto.SHA2_256, key.HashAlgo())
+	assert.Empty(t, key.ToConfig().Mnemonic)
+
+	restored, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        crypto.ECDSA_secp256k1,
+		Mnemonic:       mnemonic,
+		DerivationPath: "m/44'/539'/0'/0/0",
+	})
+	assert.NoError(t, err)
+
+	restoredKey, err := restored.PrivateKey()
+	assert.NoError(t, err)
+	pkey, err := key.PrivateKey()
+	assert.NoError(t, err)
+	assert.True(t, (*pkey).Equals(*restoredKey))
+}
+
+func Test_KMS_RefreshMetadata(t *testing.T) {
+	key, err := kmsKeyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeGoogleKMS,
+		SigAlgo:    crypto.ECDSA_P256,
+		HashAlgo:   crypto.SHA3_256,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_secp256k1, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	original := kmsPublicKey
+	defer func() { kmsPublicKey = original }()
+	kmsPublicKey = func(_ context.Context, _ cloudkms.Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+		return pkey.PublicKey(), crypto.SHA2_256, nil
+	}
+
+	assert.NoError(t, key.(*KMSKey).RefreshMetadata(context.Background()))
+	assert.Equal(t, crypto.ECDSA_secp256k1, key.SigAlgo())
+	assert.Equal(t, crypto.SHA2_256, key.HashAlgo())
+	assert.Equal(t, crypto.ECDSA_secp256k1, key.ToConfig().SigAlgo)
+
+	kmsPublicKey = func(_ context.Context, _ cloudkms.Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+		return nil, crypto.UnknownHashAlgorithm, fmt.Errorf("cloudkms: failed to fetch public key from KMS API")
+	}
+	assert.EqualError(t, key.(*KMSKey).RefreshMetadata(context.Background()), "cloudkms: failed to fetch public key from KMS API")
+	assert.Equal(t, crypto.ECDSA_secp256k1, key.SigAlgo())
+}
+
+func Test_StrictAlgorithms(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	key, err := hexKeyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeHex,
+		Index:      1,
+		SigAlgo:    crypto.ECDSA_P256,
+		PrivateKey: pkey,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, key.Validate())
+
+	SetStrictAlgorithms(true)
+	defer SetStrictAlgorithms(false)
+
+	assert.EqualError(t, key.Validate(), "key at index 1 doesn't specify a hash algorithm")
+	_, err = key.Signer(context.Background())
+	assert.EqualError(t, err, "key at index 1 doesn't specify a hash algorithm")
+
+	fileKey := NewFileKey("./test.pkey", 0, crypto.UnknownSignatureAlgorithm, crypto.SHA3_256)
+	assert.EqualError(t, fileKey.Validate(), "key at index 0 doesn't specify a signature algorithm")
+
+	assert.NoError(t, NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey).Validate())
 }
 
 func Test_File_key(t *testing.T) {
@@ -63,6 +360,135 @@ func Test_File_key(t *testing.T) {
 	assert.Equal(t, confKey, key.ToConfig())
 }
 
+func Test_FileKeyPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not checked on Windows")
+	}
+
+	location := filepath.Join(t.TempDir(), "test.pkey")
+	err := os.WriteFile(location, []byte("dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"), 0644)
+	assert.NoError(t, err)
+
+	_, err = NewFileKey(location, 0, crypto.ECDSA_P256, crypto.SHA3_256).PrivateKey()
+	assert.NoError(t, err)
+
+	SetCheckKeyFilePermissions(true)
+	defer SetCheckKeyFilePermissions(false)
+	config.SetStrict(true)
+	defer config.SetStrict(false)
+
+	_, err = NewFileKey(location, 0, crypto.ECDSA_P256, crypto.SHA3_256).PrivateKey()
+	assert.EqualError(t, err, fmt.Sprintf(
+		"key file %s has permissions 0644 which allow other users to access it, restrict them with chmod 600 %s",
+		location,
+		location,
+	))
+
+	assert.NoError(t, os.Chmod(location, 0600))
+	_, err = NewFileKey(location, 0, crypto.ECDSA_P256, crypto.SHA3_256).PrivateKey()
+	assert.NoError(t, err)
+}
+
+func Test_FileKeyConcurrentLoad(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "test.pkey")
+	err := os.WriteFile(location, []byte("dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"), 0600)
+	assert.NoError(t, err)
+
+	key := NewFileKey(location, 0, crypto.ECDSA_P256, crypto.SHA3_256)
+
+	var wg sync.WaitGroup
+	signers := make([]crypto.Signer, 50)
+	for i := range signers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			signer, err := key.Signer(context.Background())
+			assert.NoError(t, err)
+			signers[i] = signer
+		}(i)
+	}
+	wg.Wait()
+
+	// the public key is read after the signers are created since the private key computes it lazily without locking
+	for _, signer := range signers {
+		assert.Equal(t, signers[0].PublicKey().String(), signer.PublicKey().String())
+	}
+
+	// the key was loaded once and is not read from the file again
+	assert.NoError(t, os.Remove(location))
+	ready, err := key.ReadyToSign(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	_, err = key.Signer(context.Background())
+	assert.NoError(t, err)
+}
+
+func Test_BIP44ConcurrentLoad(t *testing.T) {
+	var prompts int32
+	SetSecretPrompt(func(_ string) (string, error) {
+		atomic.AddInt32(&prompts, 1)
+		return "version field tornado move level pretty inject stereo ten catalog salon swallow", nil
+	})
+	defer SetSecretPrompt(nil)
+
+	key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:     config.KeyTypeBip44,
+		SigAlgo:  crypto.ECDSA_P256,
+		HashAlgo: crypto.SHA3_256,
+	})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	signers := make([]crypto.Signer, 50)
+	for i := range signers {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			signer, err := key.Signer(context.Background())
+			assert.NoError(t, err)
+			signers[i] = signer
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := key.ReadyToSign(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, key.ToConfig().Mnemonic)
+		}()
+	}
+	wg.Wait()
+
+	// the mnemonic was prompted once for all the signers
+	assert.Equal(t, int32(1), atomic.LoadInt32(&prompts))
+	for _, signer := range signers {
+		assert.Equal(t, signers[0].PublicKey().String(), signer.PublicKey().String())
+	}
+}
+
+func Test_FileKeyHexConversion(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	hexKey := NewHexKeyFromPrivateKey(2, crypto.SHA2_256, pkey)
+	location := filepath.Join(t.TempDir(), "test.pkey")
+
+	fileKey, err := hexKey.ToFile(location)
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeFile, fileKey.Type())
+	assert.Equal(t, 2, fileKey.Index())
+	assert.Equal(t, crypto.SHA2_256, fileKey.HashAlgo())
+
+	content, err := os.ReadFile(location)
+	assert.NoError(t, err)
+	assert.Equal(t, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47", string(content))
+
+	converted, err := NewFileKey(location, 2, crypto.ECDSA_P256, crypto.SHA2_256).ToHex()
+	assert.NoError(t, err)
+	assert.Equal(t, hexKey.ToConfig(), converted.ToConfig())
+
+	_, err = NewFileKey(filepath.Join(t.TempDir(), "missing.pkey"), 0, crypto.ECDSA_P256, crypto.SHA3_256).ToHex()
+	assert.ErrorContains(t, err, "could not load the key for the account from provided location")
+}
+
 func Test_BIP44(t *testing.T) {
 	confKey := config.AccountKey{
 		Type:           config.KeyTypeBip44,
@@ -86,3 +512,899 @@ func Test_BIP44(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, pubKey, sig.PublicKey().String())
 }
+
+func Test_BIP44_VerifyDerivation(t *testing.T) {
+	const pubKey = "2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be"
+	expected, err := crypto.DecodePublicKeyHex(crypto.ECDSA_P256, pubKey)
+	assert.NoError(t, err)
+
+	confKey := config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        config.DefaultSigAlgo,
+		HashAlgo:       config.DefaultHashAlgo,
+		Mnemonic:       "version field tornado move level pretty inject stereo ten catalog salon swallow",
+		DerivationPath: "m/44'/539'/0'/0/0",
+	}
+
+	key, err := bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.NoError(t, key.(*BIP44Key).VerifyDerivation(expected))
+
+	confKey.DerivationPath = "m/44'/539'/0'/0/1"
+	key, err = bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	err = key.(*BIP44Key).VerifyDerivation(expected)
+	assert.ErrorContains(t, err, "key derived at path m/44'/539'/0'/0/1 has public key")
+	assert.ErrorContains(t, err, "check the mnemonic and the derivation path")
+}
+
+func Test_BIP44_DerivationPathVariants(t *testing.T) {
+	const pubKey = "0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be"
+
+	paths := []string{
+		"m/44'/539'/0'/0/0",
+		"44'/539'/0'/0/0",
+		"/44'/539'/0'/0/0",
+		"M/44'/539'/0'/0/0",
+		" m/44'/539'/0'/0/0 ",
+		"m / 44' / 539' / 0' / 0 / 0",
+		"m/44h/539h/0h/0/0",
+		"44H/539H/0H/0/0",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			key, err := bip44KeyFromConfig(config.AccountKey{
+				Type:           config.KeyTypeBip44,
+				Mnemonic:       "version field tornado move level pretty inject stereo ten catalog salon swallow",
+				DerivationPath: path,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "m/44'/539'/0'/0/0", key.ToConfig().DerivationPath)
+
+			pkey, err := key.PrivateKey()
+			assert.NoError(t, err)
+			assert.Equal(t, pubKey, (*pkey).PublicKey().String())
+		})
+	}
+}
+
+func Test_ValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      string
+	}{
+		{"version field tornado move level pretty inject stereo ten catalog salon swallow", ""},
+		{"  version field tornado move level pretty inject stereo ten catalog salon   swallow ", ""},
+		{"version field tornado move level pretty inject stereo ten catalog salon", "invalid mnemonic, expected 12, 15, 18, 21 or 24 words but got 11"},
+		{"Version Field tornado move level pretty inject stereo ten catalog salon swallow", ""},
+		{"version field tornado move level pretty inject stereo tenn catalog salon swallow", `invalid mnemonic, unknown word "tenn" at position 9`},
+		{"version field tornado move level pretty inject stereo Tenn catalog salon swallow", `invalid mnemonic, unknown word "Tenn" at position 9`},
+		{"field version tornado move level pretty inject stereo ten catalog salon swallow", "invalid mnemonic, the checksum does not match, make sure the words are in the correct order"},
+	}
+
+	for _, test := range tests {
+		err := ValidateMnemonic(test.mnemonic)
+		if test.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, test.err)
+		}
+	}
+}
+
+func Test_BIP44_NormalizedMnemonic(t *testing.T) {
+	mnemonics := []string{
+		"version field tornado move level pretty inject stereo ten catalog salon swallow ",
+		"  version field tornado move level pretty inject stereo ten catalog salon swallow",
+		"version  field tornado move level pretty inject stereo ten catalog salon   swallow",
+		"version field tornado move level pretty\ninject stereo ten catalog salon swallow\n",
+		"version\tfield tornado move level pretty inject stereo ten catalog salon swallow",
+		"Version Field Tornado Move Level Pretty Inject Stereo Ten Catalog Salon Swallow",
+		"VERSION FIELD TORNADO MOVE LEVEL PRETTY INJECT STEREO TEN CATALOG SALON SWALLOW",
+	}
+
+	for _, mnemonic := range mnemonics {
+		key, err := bip44KeyFromConfig(config.AccountKey{
+			Type:     config.KeyTypeBip44,
+			SigAlgo:  crypto.ECDSA_P256,
+			HashAlgo: crypto.SHA3_256,
+			Mnemonic: mnemonic,
+		})
+		assert.NoError(t, err)
+
+		pkey, err := key.PrivateKey()
+		assert.NoError(t, err, mnemonic)
+		assert.Equal(t, "0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be", (*pkey).PublicKey().String())
+		assert.Equal(t, mnemonic, key.ToConfig().Mnemonic)
+	}
+}
+
+func Test_BIP44_DefaultDerivationPath(t *testing.T) {
+	key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:     config.KeyTypeBip44,
+		SigAlgo:  crypto.ECDSA_P256,
+		HashAlgo: crypto.SHA3_256,
+		Mnemonic: "version field tornado move level pretty inject stereo ten catalog salon swallow",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "m/44'/539'/0'/0/0", key.ToConfig().DerivationPath)
+
+	pkey, err := key.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be", (*pkey).PublicKey().String())
+}
+
+func Test_BIP44_DeriveKeys(t *testing.T) {
+	key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:     config.KeyTypeBip44,
+		Index:    1,
+		SigAlgo:  crypto.ECDSA_P256,
+		HashAlgo: crypto.SHA3_256,
+		Mnemonic: "version field tornado move level pretty inject stereo ten catalog salon swallow",
+	})
+	assert.NoError(t, err)
+
+	keys, err := key.(*BIP44Key).DeriveKeys(
+		BIP44Derivation{SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256},
+		BIP44Derivation{SigAlgo: crypto.ECDSA_secp256k1, HashAlgo: crypto.SHA2_256, DerivationPath: "m/44'/539'/0'/0/1"},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	p256, err := keys[0].PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be", (*p256).PublicKey().String())
+	assert.Equal(t, "m/44'/539'/0'/0/0", keys[0].ToConfig().DerivationPath)
+
+	secp256k1, err := keys[1].PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.ECDSA_secp256k1, (*secp256k1).Algorithm())
+	assert.Equal(t, crypto.SHA2_256, keys[1].HashAlgo())
+	assert.Equal(t, "m/44'/539'/0'/0/1", keys[1].ToConfig().DerivationPath)
+	assert.Equal(t, 1, keys[1].Index())
+
+	_, err = key.(*BIP44Key).DeriveKeys(BIP44Derivation{SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA2_384})
+	assert.EqualError(t, err, "invalid hash algorithm SHA2_384 for signature algorithm ECDSA_P256")
+}
+
+func Test_BIP44_ConfigDerivations(t *testing.T) {
+	conf, err := json.NewParser().Deserialize([]byte(`{
+		"accounts": {
+			"alice": {
+				"address": "01cf0e2f2f715450",
+				"key": {
+					"type": "bip44",
+					"mnemonic": "version field tornado move level pretty inject stereo ten catalog salon swallow",
+					"derivations": [
+						{
+							"name": "alice-secp256k1",
+							"index": 1,
+							"signatureAlgorithm": "ECDSA_secp256k1",
+							"hashAlgorithm": "SHA2_256",
+							"derivationPath": "m/44'/539'/0'/0/1"
+						},
+						{
+							"name": "bob",
+							"address": "179b6b1cb6755e31",
+							"signatureAlgorithm": "ECDSA_secp256k1"
+						}
+					]
+				}
+			}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	accs, err := FromConfig(conf)
+	assert.NoError(t, err)
+	assert.Len(t, accs, 3)
+
+	alice, err := accs.ByName("alice")
+	assert.NoError(t, err)
+	expected, err := alice.Key.(*BIP44Key).DeriveKeys(
+		BIP44Derivation{SigAlgo: crypto.ECDSA_secp256k1, HashAlgo: crypto.SHA2_256, DerivationPath: "m/44'/539'/0'/0/1"},
+		BIP44Derivation{SigAlgo: crypto.ECDSA_secp256k1, HashAlgo: crypto.SHA3_256},
+	)
+	assert.NoError(t, err)
+
+	derived, err := accs.ByName("alice-secp256k1")
+	assert.NoError(t, err)
+	assert.Equal(t, alice.Address, derived.Address)
+	assert.Equal(t, 1, derived.Key.Index())
+	assert.Equal(t, crypto.ECDSA_secp256k1, derived.Key.SigAlgo())
+	assert.Equal(t, crypto.SHA2_256, derived.Key.HashAlgo())
+	samePublicKey := func(a, b Key) {
+		publicA, err := keyPublicKey(a)
+		assert.NoError(t, err)
+		publicB, err := keyPublicKey(b)
+		assert.NoError(t, err)
+		assert.True(t, publicA.Equals(publicB))
+	}
+	samePublicKey(expected[0], derived.Key)
+
+	bob, err := accs.ByName("bob")
+	assert.NoError(t, err)
+	assert.Equal(t, flow.HexToAddress("179b6b1cb6755e31"), bob.Address)
+	assert.Equal(t, "m/44'/539'/0'/0/0", bob.Key.ToConfig().DerivationPath)
+	samePublicKey(expected[1], bob.Key)
+
+	// the derived accounts are saved as the derivations of the key
+	saved := ToConfig(accs)
+	assert.Len(t, saved, 1)
+	assert.Equal(t, conf.Accounts[0].Key.Derivations, saved[0].Key.Derivations)
+}
+
+func Test_BIP44_InvalidDerivationPath(t *testing.T) {
+	_, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		Mnemonic:       "version field tornado move level pretty inject stereo ten catalog salon swallow",
+		DerivationPath: "m/44'/invalid",
+	})
+	assert.ErrorContains(t, err, "invalid derivation path m/44'/invalid")
+}
+
+func Test_BIP44_ConfigRoundTrip(t *testing.T) {
+	tests := []struct {
+		sigAlgo  crypto.SignatureAlgorithm
+		expected crypto.SignatureAlgorithm
+		pubKey   string
+	}{
+		{crypto.UnknownSignatureAlgorithm, crypto.ECDSA_P256, "0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be"},
+		{crypto.ECDSA_P256, crypto.ECDSA_P256, "0x2d6daea8b0ba5b1d5935f7846ccdd7e6f9f981e34d3c0a02a927cc79c837eba56c0f9a979195e41143495b72314ffcab60da6b7031060c80dc12f01f7f2096be"},
+		{crypto.ECDSA_secp256k1, crypto.ECDSA_secp256k1, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.sigAlgo.String(), func(t *testing.T) {
+			key, err := bip44KeyFromConfig(config.AccountKey{
+				Type:           config.KeyTypeBip44,
+				SigAlgo:        test.sigAlgo,
+				Mnemonic:       "version field tornado move level pretty inject stereo ten catalog salon swallow",
+				DerivationPath: "m/44'/539'/0'/0/0",
+			})
+			assert.NoError(t, err)
+
+			confKey := key.ToConfig()
+			assert.Equal(t, test.expected, confKey.SigAlgo)
+
+			account := Account{Name: "alice", Address: flow.HexToAddress("0x01"), Key: key}
+			raw, err := account.MarshalConfigJSON()
+			assert.NoError(t, err)
+			assert.Contains(t, string(raw), fmt.Sprintf(`"signatureAlgorithm": "%s"`, test.expected))
+
+			conf, err := json.NewParser().Deserialize([]byte(fmt.Sprintf(`{"accounts": %s}`, raw)))
+			assert.NoError(t, err)
+			accs, err := FromConfig(conf)
+			assert.NoError(t, err)
+			restored := accs[0].Key
+			assert.Equal(t, test.expected, restored.ToConfig().SigAlgo)
+
+			pkey, err := key.PrivateKey()
+			assert.NoError(t, err)
+			restoredKey, err := restored.PrivateKey()
+			assert.NoError(t, err)
+			assert.True(t, (*pkey).Equals(*restoredKey))
+			assert.Equal(t, test.expected, (*restoredKey).Algorithm())
+
+			if test.pubKey != "" {
+				assert.Equal(t, test.pubKey, (*restoredKey).PublicKey().String())
+			}
+		})
+	}
+}
+
+func Test_DefaultAlgorithms(t *testing.T) {
+	key, err := keyFromConfig(config.AccountKey{
+		Type:            config.KeyTypeKeychain,
+		KeychainService: "flow",
+		KeychainAccount: "alice",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.ECDSA_P256, key.SigAlgo())
+	assert.Equal(t, crypto.SHA3_256, key.HashAlgo())
+
+	SetDefaultAlgorithms(crypto.ECDSA_secp256k1, crypto.SHA2_256)
+	defer SetDefaultAlgorithms(config.DefaultSigAlgo, config.DefaultHashAlgo)
+
+	assert.Equal(t, crypto.ECDSA_secp256k1, key.SigAlgo())
+	assert.Equal(t, crypto.SHA2_256, key.HashAlgo())
+
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	explicit := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+	assert.Equal(t, crypto.ECDSA_P256, explicit.SigAlgo())
+	assert.Equal(t, crypto.SHA3_256, explicit.HashAlgo())
+}
+
+func Test_DefaultAlgorithmsDecode(t *testing.T) {
+	const keyHex = "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+	location := filepath.Join(t.TempDir(), "test.pkey")
+	assert.NoError(t, os.WriteFile(location, []byte(keyHex), 0600))
+
+	t.Run("File", func(t *testing.T) {
+		key, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeFile, Location: location})
+		assert.NoError(t, err)
+
+		pkey, err := key.PrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.ECDSA_P256, (*pkey).Algorithm())
+	})
+
+	t.Run("Hex", func(t *testing.T) {
+		pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, keyHex)
+		assert.NoError(t, err)
+		key, err := keyFromConfig(config.AccountKey{Type: config.KeyTypeHex, PrivateKey: pkey})
+		assert.NoError(t, err)
+		assert.NoError(t, key.Validate())
+	})
+}
+
+func Test_DeriveKeyForName(t *testing.T) {
+	const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+
+	key, err := DeriveKeyForName(mnemonic, "minter", crypto.ECDSA_P256, crypto.SHA3_256)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("m/44'/539'/0'/0/%d", nameAddressIndex("minter")), key.ToConfig().DerivationPath)
+
+	again, err := DeriveKeyForName(mnemonic, "minter", crypto.ECDSA_P256, crypto.SHA3_256)
+	assert.NoError(t, err)
+	other, err := DeriveKeyForName(mnemonic, "burner", crypto.ECDSA_P256, crypto.SHA3_256)
+	assert.NoError(t, err)
+
+	pkey, _ := key.PrivateKey()
+	againKey, _ := again.PrivateKey()
+	otherKey, _ := other.PrivateKey()
+	assert.True(t, (*pkey).Equals(*againKey))
+	assert.False(t, (*pkey).Equals(*otherKey))
+
+	// sha256("minter") starts with 0xbe9677d2, the highest bit is cleared
+	assert.Equal(t, uint32(1050048466), nameAddressIndex("minter"))
+	assert.Less(t, nameAddressIndex("minter"), uint32(1<<31))
+
+	_, err = DeriveKeyForName("invalid", "minter", crypto.ECDSA_P256, crypto.SHA3_256)
+	assert.Error(t, err)
+}
+
+func Test_ReadyToSign(t *testing.T) {
+	ctx := context.Background()
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	t.Run("Hex", func(t *testing.T) {
+		ready, err := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey).ReadyToSign(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ready)
+
+		encrypted, err := EncryptSecret([]byte("secret"), "passphrase")
+		assert.NoError(t, err)
+		key, err := hexKeyFromConfig(config.AccountKey{Type: config.KeyTypeHex, Encrypted: encrypted})
+		assert.NoError(t, err)
+
+		t.Setenv(PassphraseEnv, "")
+		ready, err = key.ReadyToSign(ctx)
+		assert.EqualError(t, err, "passphrase for encrypted key not provided, set the FLOW_KEYS_PASSPHRASE environment variable")
+		assert.False(t, ready)
+
+		t.Setenv(PassphraseEnv, "passphrase")
+		ready, err = key.ReadyToSign(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		location := filepath.Join(t.TempDir(), "account.pkey")
+		key := NewFileKey(location, 0, crypto.ECDSA_P256, crypto.SHA3_256)
+
+		ready, err := key.ReadyToSign(ctx)
+		assert.ErrorContains(t, err, "could not read the key file")
+		assert.False(t, ready)
+
+		assert.NoError(t, os.WriteFile(location, []byte(pkey.String()), 0600))
+		ready, err = key.ReadyToSign(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("KMS", func(t *testing.T) {
+		original := hasApplicationDefaultCredentials
+		defer func() { hasApplicationDefaultCredentials = original }()
+
+		key, err := kmsKeyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeGoogleKMS,
+			ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+		})
+		assert.NoError(t, err)
+
+		hasApplicationDefaultCredentials = func() bool { return false }
+		ready, err := key.ReadyToSign(ctx)
+		assert.ErrorContains(t, err, "no Google application default credentials found")
+		assert.False(t, ready)
+
+		hasApplicationDefaultCredentials = func() bool { return true }
+		ready, err = key.ReadyToSign(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("Remote HTTP", func(t *testing.T) {
+		key, err := remoteHTTPKeyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeRemoteHTTP,
+			ResourceID: "alice",
+			Endpoint:   "https://signer.example.com",
+			AuthToken:  "$REMOTE_SIGNER_TOKEN",
+		})
+		assert.NoError(t, err)
+
+		t.Setenv("REMOTE_SIGNER_TOKEN", "")
+		ready, err := key.ReadyToSign(ctx)
+		assert.EqualError(t, err, "auth token $REMOTE_SIGNER_TOKEN for the remote signer is not set")
+		assert.False(t, ready)
+
+		t.Setenv("REMOTE_SIGNER_TOKEN", "secret")
+		ready, err = key.ReadyToSign(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("WebAuthn", func(t *testing.T) {
+		key, err := webAuthnKeyFromConfig(config.AccountKey{Type: config.KeyTypeWebAuthn, CredentialID: "credential"})
+		assert.NoError(t, err)
+
+		ready, err := key.ReadyToSign(ctx)
+		assert.Error(t, err)
+		assert.False(t, ready)
+	})
+
+	t.Run("Use limited", func(t *testing.T) {
+		publicKey := pkey.PublicKey()
+		key := NewUseLimitedKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), 1)
+		ready, err := key.ReadyToSign(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ready)
+
+		signer, err := key.Signer(ctx)
+		assert.NoError(t, err)
+		sig, err := signer.Sign([]byte("message"))
+		assert.NoError(t, err)
+		valid, err := publicKey.Verify(sig, []byte("message"), crypto.NewSHA3_256())
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		ready, err = key.ReadyToSign(ctx)
+		assert.EqualError(t, err, "signer already used the allowed 1 signatures")
+		assert.False(t, ready)
+	})
+}
+
+type testSignerProvider struct {
+	keys []Key
+}
+
+func (t *testSignerProvider) Signer(_ context.Context, key Key) (crypto.Signer, error) {
+	t.keys = append(t.keys, key)
+	return nil, nil
+}
+
+func Test_SignerProvider(t *testing.T) {
+	provider := &testSignerProvider{}
+	SetSignerProvider(provider)
+	defer SetSignerProvider(nil)
+
+	kmsKey, err := kmsKeyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeGoogleKMS,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+
+	fileKey := NewFileKey("./not-existing.pkey", 0, config.DefaultSigAlgo, config.DefaultHashAlgo)
+
+	for _, key := range []Key{kmsKey, fileKey} {
+		_, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []Key{kmsKey, fileKey}, provider.keys)
+}
+
+func Test_String(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(
+		config.DefaultSigAlgo,
+		"dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad1111a",
+	)
+	assert.NoError(t, err)
+
+	hexKey := NewHexKeyFromPrivateKey(1, config.DefaultHashAlgo, pkey)
+	assert.Equal(t, "HexKey{index:1, sigAlgo:ECDSA_P256, hashAlgo:SHA3_256}", hexKey.String())
+	assert.NotContains(t, fmt.Sprintf("%v", Account{Name: "alice", Key: hexKey}), "dd72967f")
+
+	kmsKey, err := kmsKeyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeGoogleKMS,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"KMSKey{index:0, sigAlgo:ECDSA_P256, hashAlgo:SHA3_256, resourceID:projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1}",
+		fmt.Sprintf("%v", kmsKey),
+	)
+
+	bip44Key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		Mnemonic:       "version field tornado move level pretty inject stereo ten catalog salon swallow",
+		DerivationPath: "m/44'/539'/0'/0/0",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "BIP44Key{index:0, sigAlgo:ECDSA_P256, hashAlgo:SHA3_256, derivationPath:m/44'/539'/0'/0/0}", fmt.Sprintf("%s", bip44Key))
+}
+
+func Test_BIP44_SigAlgo(t *testing.T) {
+	confKey := config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        crypto.ECDSA_secp256k1,
+		HashAlgo:       config.DefaultHashAlgo,
+		Mnemonic:       "version field tornado move level pretty inject stereo ten catalog salon swallow",
+		DerivationPath: "m/44'/539'/0'/0/0",
+	}
+
+	key, err := bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	pkey, err := key.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.ECDSA_secp256k1, (*pkey).Algorithm())
+
+	confKey.SigAlgo = flowcrypto.BLSBLS12381
+	key, err = bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	_, err = key.PrivateKey()
+	assert.EqualError(t, err, "signature algorithm BLS_BLS12381 is not supported for BIP44 keys")
+}
+
+func Test_BIP44_PromptedMnemonic(t *testing.T) {
+	const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+	confKey := config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        crypto.ECDSA_P256,
+		HashAlgo:       crypto.SHA3_256,
+		DerivationPath: "m/44'/539'/0'/0/0",
+	}
+
+	key, err := bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	_, err = key.ReadyToSign(context.Background())
+	assert.EqualError(t, err, "no mnemonic configured for account")
+	assert.EqualError(t, key.Validate(), "no mnemonic configured for account, set a secret prompt to enter it when signing")
+
+	prompts := make([]string, 0)
+	entered := "not a mnemonic"
+	SetSecretPrompt(func(prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return entered, nil
+	})
+	defer SetSecretPrompt(nil)
+
+	ready, err := key.ReadyToSign(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ready)
+
+	// a mistyped mnemonic is prompted again
+	_, err = key.PrivateKey()
+	assert.EqualError(t, err, "invalid mnemonic entered for account")
+	entered = mnemonic
+	pkey, err := key.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Mnemonic for the key at derivation path m/44'/539'/0'/0/0",
+		"Mnemonic for the key at derivation path m/44'/539'/0'/0/0",
+	}, prompts)
+
+	// the mnemonic is prompted once and kept in memory
+	_, err = key.Signer(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, prompts, 2)
+
+	configured, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        crypto.ECDSA_P256,
+		HashAlgo:       crypto.SHA3_256,
+		Mnemonic:       mnemonic,
+		DerivationPath: "m/44'/539'/0'/0/0",
+	})
+	assert.NoError(t, err)
+	configuredKey, err := configured.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, (*configuredKey).String(), (*pkey).String())
+
+	// the prompted mnemonic is never written to the configuration
+	assert.Equal(t, confKey, key.ToConfig())
+
+	derived, err := key.(*BIP44Key).DeriveKeys(BIP44Derivation{SigAlgo: crypto.ECDSA_secp256k1, HashAlgo: crypto.SHA2_256})
+	assert.NoError(t, err)
+	assert.Empty(t, derived[0].ToConfig().Mnemonic)
+	assert.Len(t, prompts, 2)
+}
+
+func Test_BIP44_Curve(t *testing.T) {
+	confKey := config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        crypto.ECDSA_secp256k1,
+		HashAlgo:       crypto.SHA2_256,
+		Mnemonic:       "version field tornado move level pretty inject stereo ten catalog salon swallow",
+		DerivationPath: "m/44'/539'/0'/0/0",
+	}
+
+	inferred, err := bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	inferredKey, err := inferred.PrivateKey()
+	assert.NoError(t, err)
+
+	confKey.Curve = config.CurveSecp256k1
+	key, err := bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.NoError(t, key.Validate())
+	pkey, err := key.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, (*inferredKey).String(), (*pkey).String())
+	assert.Equal(t, config.CurveSecp256k1, key.ToConfig().Curve)
+
+	confKey.Curve = config.CurveNIST256P1
+	key, err = bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.EqualError(t, key.Validate(), "curve nist256p1 contradicts signature algorithm ECDSA_secp256k1 of the BIP44 key, the curve requires ECDSA_P256")
+
+	// ed25519 can't be used by Flow accounts, but can be configured
+	confKey.Curve = config.Curve("ed25519")
+	key, err = bip44KeyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.EqualError(t, key.Validate(), "curve ed25519 is not supported for BIP44 keys, must be nist256p1 or secp256k1")
+	_, err = key.PrivateKey()
+	assert.Error(t, err)
+}
+
+func Test_Prepare(t *testing.T) {
+	t.Run("File key", func(t *testing.T) {
+		location := filepath.Join(t.TempDir(), "test.pkey")
+		err := os.WriteFile(location, []byte("0xdd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad1111a"), 0600)
+		assert.NoError(t, err)
+
+		key := NewFileKey(location, 0, config.DefaultSigAlgo, config.DefaultHashAlgo)
+		assert.NoError(t, key.Prepare(context.Background()))
+		assert.NotNil(t, key.privateKey)
+
+		missing := NewFileKey(filepath.Join(t.TempDir(), "missing.pkey"), 0, config.DefaultSigAlgo, config.DefaultHashAlgo)
+		assert.Error(t, missing.Prepare(context.Background()))
+	})
+
+	t.Run("KMS key", func(t *testing.T) {
+		t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "credentials.json")
+		provider := &testSignerProvider{}
+		SetSignerProvider(provider)
+		defer SetSignerProvider(nil)
+
+		key, err := kmsKeyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeGoogleKMS,
+			ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, key.Prepare(context.Background()))
+		assert.Len(t, provider.keys, 1)
+	})
+}
+
+func Test_GcloudLoginArgs(t *testing.T) {
+	assert.Equal(
+		t,
+		[]string{"auth", "application-default", "login", "--project=my-project"},
+		gcloudLoginArgs("my-project", ""),
+	)
+	assert.Equal(
+		t,
+		[]string{"auth", "application-default", "login", "--project=my-project", "--account=ci@my-project.iam.gserviceaccount.com"},
+		gcloudLoginArgs("my-project", "ci@my-project.iam.gserviceaccount.com"),
+	)
+}
+
+func Test_SupportsMessageSigning(t *testing.T) {
+	kmsKey, err := kmsKeyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeGoogleKMS,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+	fileKey := NewFileKey("./test.pkey", 0, config.DefaultSigAlgo, config.DefaultHashAlgo)
+
+	for _, key := range []Key{kmsKey, fileKey} {
+		assert.True(t, key.SupportsMessageSigning())
+	}
+}
+
+func Test_ToFlowAccountKey(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	t.Run("Default weight", func(t *testing.T) {
+		key := NewHexKeyFromPrivateKey(2, crypto.SHA3_256, pkey)
+
+		accountKey, err := key.ToFlowAccountKey()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, accountKey.Index)
+		assert.Equal(t, pkey.PublicKey().String(), accountKey.PublicKey.String())
+		assert.Equal(t, crypto.ECDSA_P256, accountKey.SigAlgo)
+		assert.Equal(t, crypto.SHA3_256, accountKey.HashAlgo)
+		assert.Equal(t, flow.AccountKeyWeightThreshold, accountKey.Weight)
+		assert.False(t, accountKey.Revoked)
+	})
+
+	t.Run("Configured weight", func(t *testing.T) {
+		key, err := keyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeHex,
+			Weight:     500,
+			SigAlgo:    crypto.ECDSA_P256,
+			HashAlgo:   crypto.SHA2_256,
+			PrivateKey: pkey,
+		})
+		assert.NoError(t, err)
+
+		accountKey, err := key.ToFlowAccountKey()
+		assert.NoError(t, err)
+		assert.Equal(t, 500, accountKey.Weight)
+		assert.Equal(t, crypto.SHA2_256, accountKey.HashAlgo)
+		assert.Equal(t, 500, key.ToConfig().Weight)
+	})
+
+	t.Run("Invalid hash algorithm", func(t *testing.T) {
+		key := NewHexKeyFromPrivateKey(0, crypto.SHA2_384, pkey)
+
+		_, err := key.ToFlowAccountKey()
+		assert.ErrorContains(t, err, "invalid account key")
+	})
+}
+
+func Test_AddKeyArgs(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	publicKey := hex.EncodeToString(pkey.PublicKey().Encode())
+
+	t.Run("Hex key", func(t *testing.T) {
+		key, err := keyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeHex,
+			Weight:     500,
+			SigAlgo:    crypto.ECDSA_P256,
+			HashAlgo:   crypto.SHA3_256,
+			PrivateKey: pkey,
+		})
+		assert.NoError(t, err)
+
+		args, err := key.AddKeyArgs()
+		assert.NoError(t, err)
+		weight, _ := cadence.NewUFix64("500.0")
+		assert.Equal(t, []cadence.Value{
+			cadence.String(publicKey),
+			cadence.NewUInt8(1), // ECDSA_P256
+			cadence.NewUInt8(3), // SHA3_256
+			weight,
+		}, args)
+	})
+
+	t.Run("File key", func(t *testing.T) {
+		location := filepath.Join(t.TempDir(), "test.pkey")
+		err := os.WriteFile(location, []byte("dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"), 0600)
+		assert.NoError(t, err)
+
+		args, err := NewFileKey(location, 0, crypto.ECDSA_P256, crypto.SHA2_256).AddKeyArgs()
+		assert.NoError(t, err)
+		weight, _ := cadence.NewUFix64("1000.0")
+		assert.Equal(t, []cadence.Value{
+			cadence.String(publicKey),
+			cadence.NewUInt8(1), // ECDSA_P256
+			cadence.NewUInt8(1), // SHA2_256
+			weight,
+		}, args)
+	})
+
+	t.Run("Invalid hash algorithm", func(t *testing.T) {
+		_, err := NewHexKeyFromPrivateKey(0, crypto.SHA2_384, pkey).AddKeyArgs()
+		assert.ErrorContains(t, err, "invalid account key")
+	})
+}
+
+func Test_SameKey(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	otherKey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+	assert.NoError(t, err)
+
+	hexKey := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+
+	tests := []struct {
+		name string
+		key  Key
+		same bool
+	}{
+		{"Same", NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), true},
+		{"Different private key", NewHexKeyFromPrivateKey(0, crypto.SHA3_256, otherKey), false},
+		{"Different index", NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey), false},
+		{"Different hash algorithm", NewHexKeyFromPrivateKey(0, crypto.SHA2_256, pkey), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			same, err := SameKey(hexKey, test.key)
+			assert.NoError(t, err)
+			assert.Equal(t, test.same, same)
+		})
+	}
+
+	t.Run("Remote key", func(t *testing.T) {
+		provider := &publicKeySignerProvider{publicKey: pkey.PublicKey()}
+		SetSignerProvider(provider)
+		defer SetSignerProvider(nil)
+
+		kmsKey, err := kmsKeyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeGoogleKMS,
+			ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+		})
+		assert.NoError(t, err)
+
+		same, err := SameKey(kmsKey, kmsKey)
+		assert.NoError(t, err)
+		assert.True(t, same)
+
+		same, err = SameKey(kmsKey, hexKey)
+		assert.NoError(t, err)
+		assert.False(t, same)
+	})
+}
+
+type publicKeySignerProvider struct {
+	publicKey crypto.PublicKey
+}
+
+func (p *publicKeySignerProvider) Signer(_ context.Context, _ Key) (crypto.Signer, error) {
+	return p, nil
+}
+
+func (p *publicKeySignerProvider) Sign(_ []byte) ([]byte, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (p *publicKeySignerProvider) PublicKey() crypto.PublicKey {
+	return p.publicKey
+}
+
+func Fuzz_BIP44(f *testing.F) {
+	f.Add(make([]byte, 16), uint32(0), uint32(0), false)
+	f.Add(bytes.Repeat([]byte{0xff}, 32), uint32(1), uint32(7), true)
+
+	f.Fuzz(func(t *testing.T, entropy []byte, account uint32, index uint32, secp256k1 bool) {
+		if len(entropy) < 16 {
+			return
+		}
+		entropy = entropy[:16+(len(entropy)-16)%17/4*4] // valid entropy length is 16 - 32 bytes in multiples of 4
+
+		mnemonic, err := bip39.NewMnemonic(entropy)
+		assert.NoError(t, err)
+
+		sigAlgo := crypto.ECDSA_P256
+		if secp256k1 {
+			sigAlgo = crypto.ECDSA_secp256k1
+		}
+
+		key, err := bip44KeyFromConfig(config.AccountKey{
+			Type:           config.KeyTypeBip44,
+			SigAlgo:        sigAlgo,
+			HashAlgo:       config.DefaultHashAlgo,
+			Mnemonic:       mnemonic,
+			DerivationPath: fmt.Sprintf("m/44'/539'/%d'/0/%d", account%(1<<31), index),
+		})
+		assert.NoError(t, err)
+
+		pkey, err := key.PrivateKey()
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		decoded, err := crypto.DecodePrivateKeyHex(sigAlgo, hex.EncodeToString((*pkey).Encode()))
+		assert.NoError(t, err)
+		assert.True(t, (*pkey).Equals(decoded))
+	})
+}
@@ -0,0 +1,377 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	_ "github.com/glebarez/go-sqlite" // registers the pure Go sqlite driver
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// keystoreCheck is encrypted when the keystore is created, decrypting it verifies the passphrase.
+var keystoreCheck = []byte("flow keystore")
+
+// Keystore is a SQLite database of private keys encrypted with a key derived from a single passphrase,
+// which scales to many keys where a file per key doesn't.
+//
+// The encryption key is derived using scrypt once when the keystore is opened and each private key
+// is encrypted with AES-GCM bound to the key ID, so encrypted keys can't be swapped between IDs.
+type Keystore struct {
+	path string
+	db   *sql.DB
+	gcm  cipher.AEAD
+}
+
+// CreateKeystore creates a new keystore at the path encrypted with the passphrase.
+func CreateKeystore(path string, passphrase string) (*Keystore, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keystore %s already exists", path)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keystore %s: %w", path, err)
+	}
+
+	params := &config.EncryptedSecret{N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, 16)}
+	if _, err := rand.Read(params.Salt); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := secretCipher(params, passphrase)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE keystore (salt BLOB NOT NULL, n INTEGER NOT NULL, r INTEGER NOT NULL, p INTEGER NOT NULL, nonce BLOB NOT NULL, checksum BLOB NOT NULL);
+		CREATE TABLE keys (id TEXT PRIMARY KEY, sig_algo TEXT NOT NULL, nonce BLOB NOT NULL, ciphertext BLOB NOT NULL);`,
+	)
+	if err == nil {
+		_, err = db.Exec(
+			"INSERT INTO keystore (salt, n, r, p, nonce, checksum) VALUES (?, ?, ?, ?, ?, ?)",
+			params.Salt, params.N, params.R, params.P, nonce, gcm.Seal(nil, nonce, keystoreCheck, nil),
+		)
+	}
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create keystore %s: %w", path, err)
+	}
+
+	return &Keystore{path: path, db: db, gcm: gcm}, nil
+}
+
+// OpenKeystore opens the keystore at the path and unlocks it with the passphrase.
+func OpenKeystore(path string, passphrase string) (*Keystore, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("could not open keystore %s: %w", path, err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open keystore %s: %w", path, err)
+	}
+
+	params := &config.EncryptedSecret{}
+	var nonce, checksum []byte
+	err = db.QueryRow("SELECT salt, n, r, p, nonce, checksum FROM keystore").
+		Scan(&params.Salt, &params.N, &params.R, &params.P, &nonce, &checksum)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s is not a valid keystore: %w", path, err)
+	}
+
+	gcm, err := secretCipher(params, passphrase)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	if len(nonce) != gcm.NonceSize() {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s is not a valid keystore: invalid nonce length", path)
+	}
+	if _, err := gcm.Open(nil, nonce, checksum, nil); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to unlock keystore %s, make sure the passphrase is correct", path)
+	}
+
+	return &Keystore{path: path, db: db, gcm: gcm}, nil
+}
+
+// Add encrypts and stores the private key under the ID, replacing the key stored under the ID if any.
+func (k *Keystore) Add(id string, key crypto.PrivateKey) error {
+	nonce := make([]byte, k.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	_, err := k.db.Exec(
+		"INSERT OR REPLACE INTO keys (id, sig_algo, nonce, ciphertext) VALUES (?, ?, ?, ?)",
+		id, key.Algorithm().String(), nonce, k.gcm.Seal(nil, nonce, key.Encode(), []byte(id)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store key %s in keystore %s: %w", id, k.path, err)
+	}
+
+	return nil
+}
+
+// PrivateKey decrypts the private key stored under the ID.
+func (k *Keystore) PrivateKey(id string) (crypto.PrivateKey, error) {
+	var sigAlgo string
+	var nonce, ciphertext []byte
+	err := k.db.QueryRow("SELECT sig_algo, nonce, ciphertext FROM keys WHERE id = ?", id).Scan(&sigAlgo, &nonce, &ciphertext)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("key %s not found in keystore %s", id, k.path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key %s from keystore %s: %w", id, k.path, err)
+	}
+
+	if len(nonce) != k.gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length for key %s in keystore %s", id, k.path)
+	}
+	encoded, err := k.gcm.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt key %s in keystore %s", id, k.path)
+	}
+
+	pkey, err := crypto.DecodePrivateKey(crypto.StringToSignatureAlgorithm(sigAlgo), encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %s in keystore %s", id, k.path)
+	}
+
+	return pkey, nil
+}
+
+// IDs returns the IDs of the keys in the keystore in alphabetical order.
+func (k *Keystore) IDs() ([]string, error) {
+	rows, err := k.db.Query("SELECT id FROM keys ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys in keystore %s: %w", k.path, err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Close closes the keystore database.
+func (k *Keystore) Close() error {
+	return k.db.Close()
+}
+
+var (
+	keystoresMu sync.Mutex
+	keystores   = make(map[string]*Keystore)
+)
+
+// unlockedKeystore returns the keystore at the path, it's unlocked with the passphrase from the environment
+// or the secret prompt the first time and stays unlocked, so all the keys in the keystore share a single unlock.
+func unlockedKeystore(path string) (*Keystore, error) {
+	keystoresMu.Lock()
+	defer keystoresMu.Unlock()
+
+	if keystore, ok := keystores[path]; ok {
+		return keystore, nil
+	}
+
+	p, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	keystore, err := OpenKeystore(path, p)
+	if err != nil {
+		return nil, err
+	}
+
+	keystores[path] = keystore
+	return keystore, nil
+}
+
+var _ Key = &KeystoreKey{}
+
+// KeystoreKey is a key stored in a keystore database, referenced by its ID in the keystore.
+//
+// The private key is read and decrypted on first use, the keystore is unlocked once for all of its keys.
+type KeystoreKey struct {
+	*baseKey
+	location   string
+	id         string
+	mu         sync.Mutex
+	privateKey crypto.PrivateKey
+}
+
+func keystoreKeyFromConfig(key config.AccountKey) (*KeystoreKey, error) {
+	if key.Location == "" {
+		return nil, fmt.Errorf("missing keystore location")
+	}
+	if key.ResourceID == "" {
+		return nil, fmt.Errorf("missing key ID in keystore %s", key.Location)
+	}
+
+	return &KeystoreKey{
+		baseKey:  baseKeyFromConfig(key),
+		location: key.Location,
+		id:       key.ResourceID,
+	}, nil
+}
+
+func (k *KeystoreKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := k.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, k)
+	}
+
+	key, err := k.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemorySigner(*key, k.HashAlgo())
+}
+
+// PrivateKey decrypts the key from the keystore, the key is cached after the first load.
+func (k *KeystoreKey) PrivateKey() (*crypto.PrivateKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.privateKey == nil { // lazy load the key
+		keystore, err := unlockedKeystore(k.location)
+		if err != nil {
+			return nil, err
+		}
+
+		pkey, err := keystore.PrivateKey(k.id)
+		if err != nil {
+			return nil, err
+		}
+
+		if pkey.Algorithm() != k.SigAlgo() {
+			return nil, fmt.Errorf(
+				"key %s in keystore %s is a %s key, but the account key uses %s",
+				k.id, k.location, pkey.Algorithm(), k.SigAlgo(),
+			)
+		}
+		k.privateKey = pkey
+	}
+
+	return &k.privateKey, nil
+}
+
+func (k *KeystoreKey) Validate() error {
+	return k.ValidateCtx(context.Background())
+}
+
+// ValidateCtx unlocks the keystore and checks the key is stored in it.
+func (k *KeystoreKey) ValidateCtx(_ context.Context) error {
+	if err := k.checkAlgorithms(); err != nil {
+		return err
+	}
+
+	_, err := k.PrivateKey()
+	return err
+}
+
+// Prepare unlocks the keystore and decrypts the key.
+func (k *KeystoreKey) Prepare(ctx context.Context) error {
+	return k.ValidateCtx(ctx)
+}
+
+// ReadyToSign checks the key was loaded or the keystore exists and the passphrase to unlock it is available.
+func (k *KeystoreKey) ReadyToSign(_ context.Context) (bool, error) {
+	k.mu.Lock()
+	loaded := k.privateKey != nil
+	k.mu.Unlock()
+
+	if signerProvider != nil || loaded {
+		return true, nil
+	}
+
+	if _, err := os.Stat(k.location); err != nil {
+		return false, fmt.Errorf("could not open keystore %s: %w", k.location, err)
+	}
+
+	keystoresMu.Lock()
+	_, unlocked := keystores[k.location]
+	keystoresMu.Unlock()
+	if unlocked {
+		return true, nil
+	}
+
+	return passphraseAvailable()
+}
+
+func (k *KeystoreKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(k)
+}
+
+func (k *KeystoreKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(k)
+}
+
+func (k *KeystoreKey) ToConfig() config.AccountKey {
+	return config.AccountKey{
+		Type:       k.keyType,
+		Index:      k.index,
+		Weight:     k.weight,
+		WeightSet:  k.weightSet,
+		SigAlgo:    k.sigAlgo,
+		HashAlgo:   k.hashAlgo,
+		Location:   k.location,
+		ResourceID: k.id,
+	}
+}
+
+func (k *KeystoreKey) String() string {
+	return fmt.Sprintf("KeystoreKey{%s, location:%s, id:%s}", k.fields(), k.location, k.id)
+}
@@ -0,0 +1,128 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_Keystore(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	secpKey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_secp256k1, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+	assert.NoError(t, err)
+
+	location := filepath.Join(t.TempDir(), "keys.db")
+	keystore, err := CreateKeystore(location, "secret")
+	assert.NoError(t, err)
+	assert.NoError(t, keystore.Add("alice", pkey))
+	assert.NoError(t, keystore.Add("bob", secpKey))
+	assert.NoError(t, keystore.Close())
+
+	_, err = CreateKeystore(location, "secret")
+	assert.EqualError(t, err, "keystore "+location+" already exists")
+
+	t.Run("Open", func(t *testing.T) {
+		keystore, err := OpenKeystore(location, "secret")
+		assert.NoError(t, err)
+		defer keystore.Close()
+
+		ids, err := keystore.IDs()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob"}, ids)
+
+		stored, err := keystore.PrivateKey("bob")
+		assert.NoError(t, err)
+		assert.Equal(t, secpKey.String(), stored.String())
+
+		_, err = keystore.PrivateKey("carol")
+		assert.EqualError(t, err, "key carol not found in keystore "+location)
+
+		_, err = OpenKeystore(location, "wrong")
+		assert.EqualError(t, err, "failed to unlock keystore "+location+", make sure the passphrase is correct")
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		prompts := 0
+		SetSecretPrompt(func(string) (string, error) {
+			prompts++
+			return "secret", nil
+		})
+		defer SetSecretPrompt(nil)
+
+		newKey := func(id string, sigAlgo crypto.SignatureAlgorithm) Key {
+			key, err := keyFromConfig(config.AccountKey{
+				Type:       config.KeyTypeKeystore,
+				SigAlgo:    sigAlgo,
+				HashAlgo:   crypto.SHA3_256,
+				Location:   location,
+				ResourceID: id,
+			})
+			assert.NoError(t, err)
+			return key
+		}
+
+		alice := newKey("alice", crypto.ECDSA_P256)
+		ready, err := alice.ReadyToSign(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, ready)
+
+		signer, err := alice.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, pkey.PublicKey().String(), signer.PublicKey().String())
+
+		// the keystore is unlocked once for all of its keys
+		assert.NoError(t, newKey("bob", crypto.ECDSA_secp256k1).Validate())
+		assert.Equal(t, 1, prompts)
+
+		assert.EqualError(t, newKey("carol", crypto.ECDSA_P256).Validate(), "key carol not found in keystore "+location)
+		assert.EqualError(
+			t,
+			newKey("bob", crypto.ECDSA_P256).Validate(),
+			"key bob in keystore "+location+" is a ECDSA_secp256k1 key, but the account key uses ECDSA_P256",
+		)
+
+		assert.Equal(t, config.AccountKey{
+			Type:       config.KeyTypeKeystore,
+			SigAlgo:    crypto.ECDSA_P256,
+			HashAlgo:   crypto.SHA3_256,
+			Location:   location,
+			ResourceID: "alice",
+		}, alice.ToConfig())
+	})
+
+	t.Run("Missing keystore", func(t *testing.T) {
+		key, err := keyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeKeystore,
+			Location:   filepath.Join(t.TempDir(), "missing.db"),
+			ResourceID: "alice",
+		})
+		assert.NoError(t, err)
+
+		_, err = key.ReadyToSign(context.Background())
+		assert.ErrorContains(t, err, "could not open keystore")
+	})
+}
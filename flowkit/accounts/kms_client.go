@@ -0,0 +1,91 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+// KMSClient is the Google Cloud KMS client used by KMS keys.
+type KMSClient interface {
+	// SignerForKey returns a signer which signs using the KMS key, the client must stay open while it is used.
+	SignerForKey(ctx context.Context, key cloudkms.Key) (crypto.Signer, error)
+	// GetPublicKey returns the public key and the hash algorithm of the KMS key.
+	GetPublicKey(ctx context.Context, key cloudkms.Key) (crypto.PublicKey, crypto.HashAlgorithm, error)
+	// KeyAlgorithm returns the algorithm of the KMS key version.
+	KeyAlgorithm(ctx context.Context, key cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error)
+	// Close closes the connection to KMS.
+	Close() error
+}
+
+var kmsClientFactory func(ctx context.Context) (KMSClient, error)
+
+// SetKMSClientFactory sets the function creating the clients used by Google Cloud KMS keys, such as a fake
+// client in tests, setting it to nil restores the Google Cloud client.
+//
+// Keys using a custom client don't require Google credentials and never sign in with gcloud.
+func SetKMSClientFactory(factory func(ctx context.Context) (KMSClient, error)) {
+	kmsClientFactory = factory
+}
+
+// newKMSClient creates a client using the configured factory or the Google Cloud client.
+func newKMSClient(ctx context.Context) (KMSClient, error) {
+	if kmsClientFactory != nil {
+		return kmsClientFactory(ctx)
+	}
+
+	client, err := cloudkms.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &googleKMSClient{client}, nil
+}
+
+var _ KMSClient = &googleKMSClient{}
+
+// googleKMSClient adapts the Google Cloud KMS client to the KMSClient interface.
+type googleKMSClient struct {
+	client *cloudkms.Client
+}
+
+func (c *googleKMSClient) SignerForKey(ctx context.Context, key cloudkms.Key) (crypto.Signer, error) {
+	return c.client.SignerForKey(ctx, key)
+}
+
+func (c *googleKMSClient) GetPublicKey(ctx context.Context, key cloudkms.Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+	return c.client.GetPublicKey(ctx, key)
+}
+
+func (c *googleKMSClient) KeyAlgorithm(ctx context.Context, key cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+	result, err := c.client.KMSClient().GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: key.ResourceID()})
+	if err != nil {
+		return kmspb.CryptoKeyVersion_CRYPTO_KEY_VERSION_ALGORITHM_UNSPECIFIED,
+			fmt.Errorf("failed to fetch KMS key %s: %w", key.ResourceID(), err)
+	}
+	return result.Algorithm, nil
+}
+
+func (c *googleKMSClient) Close() error {
+	return c.client.KMSClient().Close()
+}
@@ -0,0 +1,75 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// localKeyTypes are the key types which sign in process, so measuring their signing latency is not useful.
+var localKeyTypes = []config.KeyType{
+	config.KeyTypeHex,
+	config.KeyTypeFile,
+	config.KeyTypeBip44,
+	config.KeyTypeKeychain,
+	config.KeyTypeURL,
+	config.KeyTypeCommand,
+	config.KeyTypeKeystore,
+}
+
+// MeasureSignLatency signs a dummy message the number of samples times and returns the average signing latency,
+// which helps to decide the parallelism of signing with remote keys and to find slow KMS configurations.
+//
+// Keys signing in process have a negligible latency and return zero without signing.
+func MeasureSignLatency(ctx context.Context, key Key, samples int) (time.Duration, error) {
+	if samples <= 0 {
+		return 0, fmt.Errorf("number of samples must be positive, got %d", samples)
+	}
+	if slices.Contains(localKeyTypes, key.Type()) {
+		return 0, nil
+	}
+
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	message := make([]byte, 32)
+	var total time.Duration
+	for i := 0; i < samples; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		start := time.Now()
+		_, err := signer.Sign(message)
+		if err != nil {
+			return 0, fmt.Errorf("failed to sign the latency sample: %w", err)
+		}
+		total += time.Since(start)
+	}
+
+	return total / time.Duration(samples), nil
+}
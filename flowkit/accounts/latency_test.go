@@ -0,0 +1,63 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+type slowSigner struct {
+	crypto.Signer
+	delay time.Duration
+}
+
+func (s slowSigner) Sign(message []byte) ([]byte, error) {
+	time.Sleep(s.delay)
+	return s.Signer.Sign(message)
+}
+
+func Test_MeasureSignLatency(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	hexKey := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+
+	latency, err := MeasureSignLatency(context.Background(), hexKey, 3)
+	assert.NoError(t, err)
+	assert.Zero(t, latency)
+
+	signer, err := crypto.NewInMemorySigner(pkey, crypto.SHA3_256)
+	assert.NoError(t, err)
+	remote := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+	remote.keyType = config.KeyTypeRemoteHTTP
+	key := signerKey{remote, slowSigner{signer, 10 * time.Millisecond}}
+
+	latency, err = MeasureSignLatency(context.Background(), key, 3)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, latency, 10*time.Millisecond)
+
+	_, err = MeasureSignLatency(context.Background(), key, 0)
+	assert.EqualError(t, err, "number of samples must be positive, got 0")
+}
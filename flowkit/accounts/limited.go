@@ -0,0 +1,179 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+var _ crypto.Signer = &UseLimitedSigner{}
+
+// UseLimitedSigner is a signer that refuses to sign more than the allowed number of times.
+//
+// It is useful for keys used in one-shot operations where signing again is most likely a replay accident.
+type UseLimitedSigner struct {
+	signer crypto.Signer
+	limit  int
+	mu     sync.Mutex
+	used   int
+}
+
+// NewUseLimitedSigner wraps the signer so it can be used to sign at most limit times.
+func NewUseLimitedSigner(signer crypto.Signer, limit int) *UseLimitedSigner {
+	return &UseLimitedSigner{
+		signer: signer,
+		limit:  limit,
+	}
+}
+
+func (s *UseLimitedSigner) Sign(message []byte) ([]byte, error) {
+	s.mu.Lock() // signing is serialized since the wrapped signer might not be safe for concurrent use
+	defer s.mu.Unlock()
+
+	if s.used >= s.limit {
+		return nil, fmt.Errorf("signer already used the allowed %d signatures", s.limit)
+	}
+	s.used++
+
+	return s.signer.Sign(message)
+}
+
+func (s *UseLimitedSigner) PublicKey() crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+// Remaining returns the number of signatures the signer can still produce.
+func (s *UseLimitedSigner) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limit - s.used
+}
+
+var _ Key = &UseLimitedKey{}
+
+// UseLimitedKey wraps a key so all of its signers share a single limit on the number of signatures.
+type UseLimitedKey struct {
+	Key
+	limit  int
+	mu     sync.Mutex
+	signer *UseLimitedSigner
+}
+
+// NewUseLimitedKey wraps the key so it can be used to sign at most limit times.
+func NewUseLimitedKey(key Key, limit int) *UseLimitedKey {
+	return &UseLimitedKey{
+		Key:   key,
+		limit: limit,
+	}
+}
+
+// Signer returns the limited signer, the same signer is returned on every call so the limit is shared.
+func (k *UseLimitedKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.signer != nil {
+		return k.signer, nil
+	}
+
+	signer, err := k.Key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k.signer = NewUseLimitedSigner(signer, k.limit)
+	return k.signer, nil
+}
+
+// ReadyToSign checks the limit was not reached and the wrapped key is ready.
+func (k *UseLimitedKey) ReadyToSign(ctx context.Context) (bool, error) {
+	k.mu.Lock()
+	signer := k.signer
+	k.mu.Unlock()
+
+	if signer != nil && signer.Remaining() <= 0 {
+		return false, fmt.Errorf("signer already used the allowed %d signatures", k.limit)
+	}
+	return k.Key.ReadyToSign(ctx)
+}
+
+func (k *UseLimitedKey) String() string {
+	return fmt.Sprintf("UseLimitedKey{%s, limit:%d}", k.Key, k.limit)
+}
+
+var _ crypto.Signer = &MaxSizeSigner{}
+
+// MaxSizeSigner is a signer that refuses to sign messages larger than the allowed size.
+//
+// Unexpectedly large messages are usually a sign of a bug or an attack, so services signing automatically can use it as a safeguard.
+type MaxSizeSigner struct {
+	signer  crypto.Signer
+	maxSize int
+}
+
+// NewMaxSizeSigner wraps the signer so it only signs messages of at most maxSize bytes.
+func NewMaxSizeSigner(signer crypto.Signer, maxSize int) *MaxSizeSigner {
+	return &MaxSizeSigner{
+		signer:  signer,
+		maxSize: maxSize,
+	}
+}
+
+func (s *MaxSizeSigner) Sign(message []byte) ([]byte, error) {
+	if len(message) > s.maxSize {
+		return nil, fmt.Errorf("message size of %d bytes exceeds the maximum of %d bytes", len(message), s.maxSize)
+	}
+	return s.signer.Sign(message)
+}
+
+func (s *MaxSizeSigner) PublicKey() crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+var _ Key = &MaxSizeKey{}
+
+// MaxSizeKey wraps a key so its signers only sign messages of at most the allowed size.
+type MaxSizeKey struct {
+	Key
+	maxSize int
+}
+
+// NewMaxSizeKey wraps the key so it only signs messages of at most maxSize bytes.
+func NewMaxSizeKey(key Key, maxSize int) *MaxSizeKey {
+	return &MaxSizeKey{
+		Key:     key,
+		maxSize: maxSize,
+	}
+}
+
+func (k *MaxSizeKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	signer, err := k.Key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewMaxSizeSigner(signer, k.maxSize), nil
+}
+
+func (k *MaxSizeKey) String() string {
+	return fmt.Sprintf("MaxSizeKey{%s, maxSize:%d}", k.Key, k.maxSize)
+}
@@ -0,0 +1,89 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_UseLimitedSigner(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	t.Run("Limit", func(t *testing.T) {
+		signer, err := crypto.NewInMemorySigner(pkey, crypto.SHA3_256)
+		assert.NoError(t, err)
+
+		limited := NewUseLimitedSigner(signer, 1)
+		assert.Equal(t, signer.PublicKey(), limited.PublicKey())
+
+		_, err = limited.Sign([]byte("message"))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, limited.Remaining())
+
+		_, err = limited.Sign([]byte("message"))
+		assert.EqualError(t, err, "signer already used the allowed 1 signatures")
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		key := NewUseLimitedKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), 5)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		signed := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				signer, err := key.Signer(context.Background())
+				assert.NoError(t, err)
+
+				if _, err := signer.Sign([]byte("message")); err == nil {
+					mu.Lock()
+					signed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 5, signed)
+	})
+}
+
+func Test_MaxSizeSigner(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	key := NewMaxSizeKey(NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), 8)
+	signer, err := key.Signer(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, pkey.PublicKey(), signer.PublicKey())
+
+	_, err = signer.Sign([]byte("12345678"))
+	assert.NoError(t, err)
+
+	_, err = signer.Sign([]byte("123456789"))
+	assert.EqualError(t, err, "message size of 9 bytes exceeds the maximum of 8 bytes")
+}
@@ -0,0 +1,106 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+
+	"github.com/onflow/flow-cli/flowkit/accounts"
+)
+
+var _ accounts.KMSClient = &FakeKMSClient{}
+
+// FakeKMSClient is a KMS client holding the keys in memory, it signs deterministically using RFC 6979.
+//
+// Use it with accounts.SetKMSClientFactory to exercise KMS keys in tests without Google credentials.
+// Keys are registered by resource ID and, like Google Cloud KMS EC keys, sign using SHA2-256.
+type FakeKMSClient struct {
+	mu   sync.Mutex
+	keys map[string]crypto.PrivateKey
+}
+
+// NewFakeKMSClient creates a fake KMS client without keys.
+func NewFakeKMSClient() *FakeKMSClient {
+	return &FakeKMSClient{
+		keys: make(map[string]crypto.PrivateKey),
+	}
+}
+
+// AddKey registers the private key under the KMS resource ID, it must be a P-256 or secp256k1 key.
+func (f *FakeKMSClient) AddKey(resourceID string, privateKey crypto.PrivateKey) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[resourceID] = privateKey
+}
+
+// Factory returns a client factory for accounts.SetKMSClientFactory which always returns the fake client.
+func (f *FakeKMSClient) Factory() func(ctx context.Context) (accounts.KMSClient, error) {
+	return func(_ context.Context) (accounts.KMSClient, error) {
+		return f, nil
+	}
+}
+
+func (f *FakeKMSClient) SignerForKey(_ context.Context, key cloudkms.Key) (crypto.Signer, error) {
+	privateKey, err := f.key(key)
+	if err != nil {
+		return nil, err
+	}
+	return accounts.NewDeterministicSigner(privateKey, crypto.SHA2_256)
+}
+
+func (f *FakeKMSClient) GetPublicKey(_ context.Context, key cloudkms.Key) (crypto.PublicKey, crypto.HashAlgorithm, error) {
+	privateKey, err := f.key(key)
+	if err != nil {
+		return nil, crypto.UnknownHashAlgorithm, err
+	}
+	return privateKey.PublicKey(), crypto.SHA2_256, nil
+}
+
+func (f *FakeKMSClient) KeyAlgorithm(_ context.Context, key cloudkms.Key) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+	privateKey, err := f.key(key)
+	if err != nil {
+		return kmspb.CryptoKeyVersion_CRYPTO_KEY_VERSION_ALGORITHM_UNSPECIFIED, err
+	}
+
+	if privateKey.Algorithm() == crypto.ECDSA_secp256k1 {
+		return kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256, nil
+	}
+	return kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256, nil
+}
+
+func (f *FakeKMSClient) Close() error {
+	return nil
+}
+
+func (f *FakeKMSClient) key(key cloudkms.Key) (crypto.PrivateKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	privateKey, ok := f.keys[key.ResourceID()]
+	if !ok {
+		return nil, fmt.Errorf("failed to fetch KMS key %s: key not found", key.ResourceID())
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,93 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mocks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/accounts"
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_FakeKMSClient(t *testing.T) {
+	const resourceID = "projects/flow/locations/global/keyRings/test/cryptoKeys/signer/cryptoKeyVersions/1"
+
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	client := NewFakeKMSClient()
+	client.AddKey(resourceID, pkey)
+	accounts.SetKMSClientFactory(client.Factory())
+	defer accounts.SetKMSClientFactory(nil)
+
+	accs, err := accounts.FromConfig(&config.Config{
+		Accounts: config.Accounts{{
+			Name:    "kms",
+			Address: flow.HexToAddress("0x01"),
+			Key: config.AccountKey{
+				Type:       config.KeyTypeGoogleKMS,
+				SigAlgo:    crypto.ECDSA_P256,
+				HashAlgo:   crypto.SHA2_256,
+				ResourceID: resourceID,
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	account := &accs[0]
+
+	t.Run("Sign transaction", func(t *testing.T) {
+		err := account.Key.ValidateCtx(context.Background())
+		assert.NoError(t, err)
+
+		ready, err := account.Key.ReadyToSign(context.Background())
+		assert.True(t, ready)
+		assert.NoError(t, err)
+
+		tx := flow.NewTransaction().SetProposalKey(account.Address, 0, 0).SetPayer(account.Address)
+		err = account.SignTransaction(context.Background(), tx, accounts.SignRolePayer)
+		assert.NoError(t, err)
+
+		message := append(flow.TransactionDomainTag[:], tx.EnvelopeMessage()...)
+		valid, err := pkey.PublicKey().Verify(tx.EnvelopeSignatures[0].Signature, message, crypto.NewSHA2_256())
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		// signing is deterministic
+		first := tx.EnvelopeSignatures[0].Signature
+		err = account.SignTransaction(context.Background(), tx, accounts.SignRolePayer)
+		assert.NoError(t, err)
+		assert.Equal(t, first, tx.EnvelopeSignatures[1].Signature)
+	})
+
+	t.Run("Public key", func(t *testing.T) {
+		flowKey, err := account.Key.ToFlowAccountKey()
+		assert.NoError(t, err)
+		assert.Equal(t, pkey.PublicKey().String(), flowKey.PublicKey.String())
+	})
+
+	t.Run("Fail unknown key", func(t *testing.T) {
+		_, err := accounts.NewRotatedKMSKey(context.Background(), account.Key, resourceID[:len(resourceID)-1]+"2", 1)
+		assert.ErrorContains(t, err, "key not found")
+	})
+}
@@ -0,0 +1,84 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mocks
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/accounts"
+)
+
+var _ accounts.SignerProvider = &MockSignerProvider{}
+
+var _ crypto.Signer = &MockSigner{}
+
+// MockSignerProvider provides signers producing deterministic signatures for any key type.
+//
+// Use it with accounts.SetSignerProvider to avoid real signing backends (KMS, gcloud) in tests.
+type MockSignerProvider struct{}
+
+func (m *MockSignerProvider) Signer(_ context.Context, key accounts.Key) (crypto.Signer, error) {
+	return NewMockSigner(key.Index(), key.SigAlgo(), key.HashAlgo())
+}
+
+// MockSigner signs messages by hashing the key index together with the message.
+//
+// The public key is generated from a seed derived from the key index, so signers for the
+// same index and algorithms always have the same public key.
+type MockSigner struct {
+	index     int
+	hashAlgo  crypto.HashAlgorithm
+	publicKey crypto.PublicKey
+}
+
+// NewMockSigner creates a new deterministic signer for the key index.
+func NewMockSigner(index int, sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) (*MockSigner, error) {
+	seed := make([]byte, crypto.MinSeedLength)
+	binary.BigEndian.PutUint64(seed, uint64(index))
+
+	privateKey, err := crypto.GeneratePrivateKey(sigAlgo, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MockSigner{
+		index:     index,
+		hashAlgo:  hashAlgo,
+		publicKey: privateKey.PublicKey(),
+	}, nil
+}
+
+func (m *MockSigner) Sign(message []byte) ([]byte, error) {
+	hasher, err := crypto.NewHasher(m.hashAlgo)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 8, 8+len(message))
+	binary.BigEndian.PutUint64(data, uint64(m.index))
+
+	return hasher.ComputeHash(append(data, message...)), nil
+}
+
+func (m *MockSigner) PublicKey() crypto.PublicKey {
+	return m.publicKey
+}
@@ -0,0 +1,81 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// SigningPolicy decides whether the account may sign the transaction for the role,
+// it returns an error describing the violation if signing is denied.
+type SigningPolicy func(tx *flow.Transaction, role SignRole) error
+
+// AllPolicies combines the policies so signing is allowed only if all the policies allow it, nil policies are ignored.
+func AllPolicies(policies ...SigningPolicy) SigningPolicy {
+	return func(tx *flow.Transaction, role SignRole) error {
+		for _, policy := range policies {
+			if policy == nil {
+				continue
+			}
+			if err := policy(tx, role); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ScriptHash returns the hex encoded SHA3-256 hash of the transaction script, as used by DenyScriptHashes.
+func ScriptHash(script []byte) string {
+	return hex.EncodeToString(crypto.NewSHA3_256().ComputeHash(script))
+}
+
+// DenyScriptHashes creates a policy denying signing transactions with any of the script hashes.
+func DenyScriptHashes(hashes ...string) SigningPolicy {
+	denied := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		denied[hash] = true
+	}
+
+	return func(tx *flow.Transaction, _ SignRole) error {
+		hash := ScriptHash(tx.Script)
+		if denied[hash] {
+			return fmt.Errorf("transaction script with hash %s is denied", hash)
+		}
+		return nil
+	}
+}
+
+// contractDeployment matches the account contract functions adding or updating contracts.
+var contractDeployment = regexp.MustCompile(`\.contracts\.(add|update__experimental|update)\s*\(`)
+
+// DenyContractDeployments creates a policy denying signing transactions which add or update account contracts.
+func DenyContractDeployments() SigningPolicy {
+	return func(tx *flow.Transaction, _ SignRole) error {
+		if contractDeployment.Match(tx.Script) {
+			return fmt.Errorf("contract deployments are denied")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_SigningPolicy(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	deploy := []byte(`transaction(name: String, code: String) {
+		prepare(signer: AuthAccount) {
+			signer.contracts.add(name: name, code: code.decodeHex())
+		}
+	}`)
+	transfer := []byte(`transaction { prepare(signer: AuthAccount) {} }`)
+
+	newTx := func(script []byte, address flow.Address) *flow.Transaction {
+		return flow.NewTransaction().
+			SetScript(script).
+			SetProposalKey(address, 0, 0).
+			SetPayer(address).
+			AddAuthorizer(address)
+	}
+
+	account := &Account{
+		Name:          "production",
+		Address:       flow.HexToAddress("0x01"),
+		Key:           NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+		SigningPolicy: AllPolicies(DenyContractDeployments(), nil),
+	}
+
+	t.Run("Deny contract deployments", func(t *testing.T) {
+		tx := newTx(deploy, account.Address)
+		err := account.SignTransaction(context.Background(), tx, SignRolePayer)
+		assert.EqualError(t, err, "signing policy of account production denied signing as payer: contract deployments are denied")
+		assert.Empty(t, tx.EnvelopeSignatures)
+
+		tx = newTx(transfer, account.Address)
+		err = account.SignTransaction(context.Background(), tx, SignRolePayer)
+		assert.NoError(t, err)
+		assert.Len(t, tx.EnvelopeSignatures, 1)
+	})
+
+	t.Run("Deny script hashes", func(t *testing.T) {
+		denied := *account
+		denied.SigningPolicy = DenyScriptHashes(ScriptHash(transfer))
+
+		err := denied.SignTransaction(context.Background(), newTx(transfer, account.Address), SignRoleAuthorizer)
+		assert.EqualError(t, err, "signing policy of account production denied signing as authorizer: transaction script with hash "+ScriptHash(transfer)+" is denied")
+
+		err = denied.SignTransaction(context.Background(), newTx(deploy, account.Address), SignRoleAuthorizer)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		other := &Account{
+			Name:          "production",
+			Address:       account.Address,
+			SigningPolicy: DenyScriptHashes(ScriptHash(transfer)),
+		}
+
+		merged, err := MergeAccounts(account, other)
+		assert.NoError(t, err)
+		assert.Error(t, merged.SigningPolicy(newTx(deploy, account.Address), SignRolePayer))
+		assert.Error(t, merged.SigningPolicy(newTx(transfer, account.Address), SignRolePayer))
+	})
+}
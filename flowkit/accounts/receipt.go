@@ -0,0 +1,114 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// DerivationReceipt documents the mnemonic and the derivation path a key exported as hex was derived from.
+//
+// The mnemonic is only included as a salted hash, so the receipt can be stored next to the exported key and
+// a later audit can confirm the key came from the expected mnemonic without revealing the mnemonic.
+type DerivationReceipt struct {
+	DerivationPath string
+	SigAlgo        crypto.SignatureAlgorithm
+	PublicKey      crypto.PublicKey
+	Salt           []byte
+	MnemonicHash   []byte
+}
+
+// ExportWithReceipt exports the derived key as a hex key together with the receipt of its derivation.
+func (a *BIP44Key) ExportWithReceipt() (*HexKey, DerivationReceipt, error) {
+	pkey, err := a.PrivateKey()
+	if err != nil {
+		return nil, DerivationReceipt{}, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, DerivationReceipt{}, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	base := *a.baseKey
+	base.keyType = config.KeyTypeHex
+	key := &HexKey{
+		baseKey:    &base,
+		privateKey: *pkey,
+	}
+
+	return key, DerivationReceipt{
+		DerivationPath: a.derivationPath,
+		SigAlgo:        a.SigAlgo(),
+		PublicKey:      (*pkey).PublicKey(),
+		Salt:           salt,
+		MnemonicHash:   receiptMnemonicHash(salt, a.loadedMnemonic()),
+	}, nil
+}
+
+// Verify checks the mnemonic is the one the receipt was issued for and the key is the one derived
+// from it at the derivation path of the receipt.
+func (r DerivationReceipt) Verify(mnemonic string, key Key) error {
+	if !bytes.Equal(receiptMnemonicHash(r.Salt, mnemonic), r.MnemonicHash) {
+		return fmt.Errorf("mnemonic does not match the derivation receipt")
+	}
+
+	derived, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		SigAlgo:        r.SigAlgo,
+		Mnemonic:       mnemonic,
+		DerivationPath: r.DerivationPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	expected, err := keyPublicKey(derived)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := keyPublicKey(key)
+	if err != nil {
+		return err
+	}
+
+	if !publicKey.Equals(expected) || (r.PublicKey != nil && !r.PublicKey.Equals(expected)) {
+		return fmt.Errorf("key was not derived at path %s from the mnemonic of the derivation receipt", r.DerivationPath)
+	}
+
+	return nil
+}
+
+// receiptMnemonicHash hashes the normalized mnemonic with the salt, the mnemonic is normalized
+// so the receipt verifies with the mnemonic written with different spacing or casing.
+func receiptMnemonicHash(salt []byte, mnemonic string) []byte {
+	hash := sha256.New()
+	hash.Write([]byte("flow-derivation-receipt:"))
+	hash.Write(salt)
+	hash.Write([]byte(NormalizeMnemonic(mnemonic)))
+	return hash.Sum(nil)
+}
@@ -0,0 +1,79 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+func Test_ExportWithReceipt(t *testing.T) {
+	const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+	const otherMnemonic = "mad aspect pill fancy grain pill glad bleak vault bag roof hungry"
+
+	key, err := bip44KeyFromConfig(config.AccountKey{
+		Type:           config.KeyTypeBip44,
+		Index:          1,
+		SigAlgo:        crypto.ECDSA_P256,
+		HashAlgo:       crypto.SHA2_256,
+		Mnemonic:       mnemonic,
+		DerivationPath: "m/44'/539'/0'/0/3",
+	})
+	assert.NoError(t, err)
+
+	hexKey, receipt, err := key.(*BIP44Key).ExportWithReceipt()
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeHex, hexKey.Type())
+	assert.Equal(t, 1, hexKey.Index())
+	assert.Equal(t, crypto.SHA2_256, hexKey.HashAlgo())
+	assert.Equal(t, "m/44'/539'/0'/0/3", receipt.DerivationPath)
+	assert.NotContains(t, string(receipt.MnemonicHash), "version")
+
+	pkey, err := key.PrivateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, (*pkey).String(), hexKey.privateKey.String())
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, receipt.Verify(mnemonic, hexKey))
+		assert.NoError(t, receipt.Verify("  VERSION field tornado move level pretty inject stereo ten catalog salon swallow", hexKey))
+	})
+
+	t.Run("Other mnemonic", func(t *testing.T) {
+		assert.EqualError(t, receipt.Verify(otherMnemonic, hexKey), "mnemonic does not match the derivation receipt")
+	})
+
+	t.Run("Other key", func(t *testing.T) {
+		other, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+		assert.NoError(t, err)
+
+		err = receipt.Verify(mnemonic, NewHexKeyFromPrivateKey(1, crypto.SHA2_256, other))
+		assert.EqualError(t, err, "key was not derived at path m/44'/539'/0'/0/3 from the mnemonic of the derivation receipt")
+	})
+
+	t.Run("Salted", func(t *testing.T) {
+		_, again, err := key.(*BIP44Key).ExportWithReceipt()
+		assert.NoError(t, err)
+		assert.NotEqual(t, receipt.MnemonicHash, again.MnemonicHash)
+		assert.NoError(t, again.Verify(mnemonic, hexKey))
+	})
+}
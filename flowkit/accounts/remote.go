@@ -0,0 +1,253 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+var _ Key = &RemoteHTTPKey{}
+
+// RemoteHTTPKey implements signing using a remote signing service over HTTP.
+//
+// The service must expose two endpoints:
+//   - GET <endpoint>/keys/<key ID> returning the public key as {"publicKey": "<hex>"}
+//   - POST <endpoint>/keys/<key ID>/sign with the body {"digest": "<hex>", "hashAlgorithm": "<algo>"}
+//     returning the signature as {"signature": "<hex>"}
+//
+// The signature can be returned in the raw r || s format or DER encoded, the format is detected
+// from the returned bytes unless it's set in the configuration.
+//
+// Requests are authenticated with a bearer token if provided, the token value can reference an environment variable.
+type RemoteHTTPKey struct {
+	*baseKey
+	endpoint           string
+	keyID              string
+	authToken          string
+	insecureSkipVerify bool
+	signatureFormat    config.SignatureFormat
+}
+
+func remoteHTTPKeyFromConfig(key config.AccountKey) (*RemoteHTTPKey, error) {
+	endpoint, err := url.Parse(key.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid remote signer endpoint %s", key.Endpoint)
+	}
+
+	if key.ResourceID == "" {
+		return nil, fmt.Errorf("missing key ID for remote signer %s", key.Endpoint)
+	}
+
+	return &RemoteHTTPKey{
+		baseKey:            baseKeyFromConfig(key),
+		endpoint:           strings.TrimSuffix(key.Endpoint, "/"),
+		keyID:              key.ResourceID,
+		authToken:          key.AuthToken,
+		insecureSkipVerify: key.InsecureSkipVerify,
+		signatureFormat:    key.SignatureFormat,
+	}, nil
+}
+
+func (r *RemoteHTTPKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := r.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, r)
+	}
+
+	signer := &remoteHTTPSigner{
+		ctx:    ctx,
+		key:    r,
+		client: r.client(),
+	}
+
+	var res struct {
+		PublicKey string `json:"publicKey"`
+	}
+	err := signer.do(http.MethodGet, r.keyURL(), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	signer.publicKey, err = crypto.DecodePublicKeyHex(r.SigAlgo(), strings.TrimPrefix(res.PublicKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid public key: %w", err)
+	}
+
+	return canonicalSigner(r.SigAlgo(), signer), nil
+}
+
+// ReadyToSign checks the configured auth token resolves to a value, reaching the service requires network access.
+func (r *RemoteHTTPKey) ReadyToSign(_ context.Context) (bool, error) {
+	if signerProvider != nil || r.authToken == "" || os.ExpandEnv(r.authToken) != "" {
+		return true, nil
+	}
+	return false, fmt.Errorf("auth token %s for the remote signer is not set", r.authToken)
+}
+
+func (r *RemoteHTTPKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(r)
+}
+
+func (r *RemoteHTTPKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(r)
+}
+
+func (r *RemoteHTTPKey) PrivateKey() (*crypto.PrivateKey, error) {
+	return nil, fmt.Errorf("private key not accessible")
+}
+
+func (r *RemoteHTTPKey) ToConfig() config.AccountKey {
+	return config.AccountKey{
+		Type:               r.keyType,
+		Index:              r.index,
+		Weight:             r.weight,
+		WeightSet:          r.weightSet,
+		SigAlgo:            r.sigAlgo,
+		HashAlgo:           r.hashAlgo,
+		ResourceID:         r.keyID,
+		Endpoint:           r.endpoint,
+		AuthToken:          r.authToken,
+		InsecureSkipVerify: r.insecureSkipVerify,
+		SignatureFormat:    r.signatureFormat,
+	}
+}
+
+func (r *RemoteHTTPKey) String() string {
+	return fmt.Sprintf("RemoteHTTPKey{%s, endpoint:%s, keyID:%s}", r.fields(), r.endpoint, r.keyID)
+}
+
+func (r *RemoteHTTPKey) keyURL() string {
+	return fmt.Sprintf("%s/keys/%s", r.endpoint, url.PathEscape(r.keyID))
+}
+
+func (r *RemoteHTTPKey) client() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if r.insecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+var _ crypto.Signer = &remoteHTTPSigner{}
+
+// remoteHTTPSigner signs by sending the message digest to the remote signing service.
+type remoteHTTPSigner struct {
+	ctx       context.Context
+	key       *RemoteHTTPKey
+	client    *http.Client
+	publicKey crypto.PublicKey
+}
+
+func (s *remoteHTTPSigner) Sign(message []byte) ([]byte, error) {
+	hasher, err := crypto.NewHasher(s.key.HashAlgo())
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"digest":        hex.EncodeToString(hasher.ComputeHash(message)),
+		"hashAlgorithm": s.key.HashAlgo().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Signature string `json:"signature"`
+	}
+	err = s.do(http.MethodPost, fmt.Sprintf("%s/sign", s.key.keyURL()), body, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(res.Signature, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid signature: %w", err)
+	}
+
+	sig, err = decodeECDSASignature(sig, s.key.signatureFormat)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid signature: %w", err)
+	}
+
+	return sig, nil
+}
+
+func (s *remoteHTTPSigner) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
+
+// do sends the request to the remote signer and decodes the JSON response into the result.
+func (s *remoteHTTPSigner) do(method string, target string, body []byte, result any) error {
+	req, err := http.NewRequestWithContext(s.ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if token := os.ExpandEnv(s.key.authToken); token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	if id := CorrelationID(s.ctx); id != "" {
+		req.Header.Set(CorrelationIDHeader, id)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	// limit the response size, the responses only contain keys and signatures
+	data, err := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	if err != nil {
+		return fmt.Errorf("failed to read remote signer response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer returned status %d: %s", res.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return fmt.Errorf("failed to decode remote signer response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// KMSSignerFactory creates a signer for a key of a KMS provider without a built-in backend,
+// the key is the parsed resource ID as returned by ParseResourceID.
+type KMSSignerFactory func(ctx context.Context, key any, hashAlgo crypto.HashAlgorithm) (crypto.Signer, error)
+
+var kmsSignerFactories = make(map[config.KeyType]KMSSignerFactory)
+
+// RegisterKMSProvider sets the signer factory used by kms keys of the provider, such as aws-kms or azure-kms,
+// setting it to nil removes the factory. Google Cloud KMS keys always use the built-in backend.
+func RegisterKMSProvider(provider config.KeyType, factory KMSSignerFactory) {
+	if factory == nil {
+		delete(kmsSignerFactories, provider)
+		return
+	}
+	kmsSignerFactories[provider] = factory
+}
+
+var _ Key = &RemoteKMSKey{}
+
+// RemoteKMSKey is a key stored in a KMS with the provider detected from the resource ID,
+// the backend used for signing is selected when the signer is created.
+//
+// Google Cloud KMS keys are signed using the same backend as KMSKey, other providers require
+// a signer factory registered with RegisterKMSProvider.
+type RemoteKMSKey struct {
+	*baseKey
+	resourceID string
+	provider   config.KeyType
+	resource   any
+	google     *KMSKey
+}
+
+func remoteKMSKeyFromConfig(key config.AccountKey) (*RemoteKMSKey, error) {
+	provider, resource, err := ParseResourceID(key.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteKey := &RemoteKMSKey{
+		baseKey:    baseKeyFromConfig(key),
+		resourceID: strings.TrimSpace(key.ResourceID),
+		provider:   provider,
+		resource:   resource,
+	}
+
+	if provider == config.KeyTypeGoogleKMS {
+		remoteKey.google = &KMSKey{
+			baseKey:       remoteKey.baseKey,
+			kmsKey:        resource.(cloudkms.Key),
+			gcloudAccount: key.GcloudAccount,
+		}
+	}
+
+	return remoteKey, nil
+}
+
+// Provider returns the KMS provider detected from the resource ID.
+func (k *RemoteKMSKey) Provider() config.KeyType {
+	return k.provider
+}
+
+func (k *RemoteKMSKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := k.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, k)
+	}
+
+	if k.google != nil {
+		return k.google.Signer(ctx)
+	}
+
+	factory, ok := kmsSignerFactories[k.provider]
+	if !ok {
+		return nil, fmt.Errorf("%s keys are not supported, register a signer using RegisterKMSProvider", k.provider)
+	}
+
+	signer, err := factory(ctx, k.resource, k.HashAlgo())
+	if err != nil {
+		return nil, err
+	}
+
+	return canonicalSigner(k.SigAlgo(), signer), nil
+}
+
+func (k *RemoteKMSKey) Validate() error {
+	return k.ValidateCtx(context.Background())
+}
+
+func (k *RemoteKMSKey) ValidateCtx(ctx context.Context) error {
+	if err := k.checkAlgorithms(); err != nil {
+		return err
+	}
+
+	if k.google != nil {
+		return k.google.ValidateCtx(ctx)
+	}
+	return nil
+}
+
+func (k *RemoteKMSKey) Prepare(ctx context.Context) error {
+	if k.google != nil {
+		return k.google.Prepare(ctx)
+	}
+	return nil
+}
+
+// ReadyToSign checks the credentials of the built-in backend or that a signer factory is registered for the provider.
+func (k *RemoteKMSKey) ReadyToSign(ctx context.Context) (bool, error) {
+	if signerProvider != nil {
+		return true, nil
+	}
+	if k.google != nil {
+		return k.google.ReadyToSign(ctx)
+	}
+	if _, ok := kmsSignerFactories[k.provider]; !ok {
+		return false, fmt.Errorf("%s keys are not supported, register a signer using RegisterKMSProvider", k.provider)
+	}
+	return true, nil
+}
+
+func (k *RemoteKMSKey) PrivateKey() (*crypto.PrivateKey, error) {
+	return nil, fmt.Errorf("private key not accessible")
+}
+
+func (k *RemoteKMSKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(k)
+}
+
+func (k *RemoteKMSKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(k)
+}
+
+func (k *RemoteKMSKey) ToConfig() config.AccountKey {
+	conf := config.AccountKey{
+		Type:       k.keyType,
+		Index:      k.index,
+		Weight:     k.weight,
+		WeightSet:  k.weightSet,
+		SigAlgo:    k.sigAlgo,
+		HashAlgo:   k.hashAlgo,
+		ResourceID: k.resourceID,
+	}
+	if k.google != nil {
+		conf.GcloudAccount = k.google.gcloudAccount
+	}
+	return conf
+}
+
+func (k *RemoteKMSKey) String() string {
+	return fmt.Sprintf("RemoteKMSKey{%s, provider:%s, resourceID:%s}", k.fields(), k.provider, k.resourceID)
+}
+
+// NewRotatedKMSKey creates a key for the KMS resource replacing the old KMS key, such as a new key version.
+//
+// The new key keeps the weight and algorithms of the old key and uses the new index, which must be the index
+// at which the key is added to the account, that is the number of keys on the account including revoked keys.
+// The new key is checked to be accessible by fetching its public key, so it can be added to the account before
+// the old key is revoked. The configuration must be updated with the new key once it's added, since the old
+// key index can no longer be used for signing after the old key is revoked.
+func NewRotatedKMSKey(ctx context.Context, old Key, newResourceID string, newIndex int) (Key, error) {
+	conf := old.ToConfig()
+	if conf.Type != config.KeyTypeGoogleKMS && conf.Type != config.KeyTypeKMS {
+		return nil, fmt.Errorf("can not rotate %s key, only KMS keys can be rotated", conf.Type)
+	}
+	if strings.TrimSpace(newResourceID) == strings.TrimSpace(conf.ResourceID) {
+		return nil, fmt.Errorf("new KMS resource ID is the same as the old one")
+	}
+	if newIndex < 0 || newIndex == old.Index() {
+		return nil, fmt.Errorf("invalid index %d of the new KMS key, must be the next free index of the account", newIndex)
+	}
+
+	conf.ResourceID = newResourceID
+	conf.Index = newIndex
+	key, err := keyFromConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = key.ValidateCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("new KMS key %s is not accessible: %w", newResourceID, err)
+	}
+
+	ready, err := key.ReadyToSign(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("new KMS key %s is not accessible: %w", newResourceID, err)
+	}
+	if !ready {
+		return nil, fmt.Errorf("new KMS key %s is not ready to sign", newResourceID)
+	}
+
+	_, err = key.ToFlowAccountKey()
+	if err != nil {
+		return nil, fmt.Errorf("new KMS key %s is not accessible: %w", newResourceID, err)
+	}
+
+	return key, nil
+}
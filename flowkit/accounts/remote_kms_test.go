@@ -0,0 +1,128 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_RemoteKMSKey(t *testing.T) {
+	awsConf := config.AccountKey{
+		Type:       config.KeyTypeKMS,
+		SigAlgo:    crypto.ECDSA_P256,
+		HashAlgo:   crypto.SHA3_256,
+		ResourceID: "arn:aws:kms:us-east-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+	}
+
+	t.Run("Unsupported provider", func(t *testing.T) {
+		key, err := keyFromConfig(awsConf)
+		assert.NoError(t, err)
+		assert.Equal(t, config.KeyTypeAWSKMS, key.(*RemoteKMSKey).Provider())
+		assert.Equal(t, awsConf, key.ToConfig())
+
+		_, err = key.Signer(context.Background())
+		assert.EqualError(t, err, "aws-kms keys are not supported, register a signer using RegisterKMSProvider")
+
+		ready, err := key.ReadyToSign(context.Background())
+		assert.False(t, ready)
+		assert.Error(t, err)
+	})
+
+	t.Run("Registered provider", func(t *testing.T) {
+		pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+		var received any
+		RegisterKMSProvider(config.KeyTypeAWSKMS, func(_ context.Context, key any, hashAlgo crypto.HashAlgorithm) (crypto.Signer, error) {
+			received = key
+			return crypto.NewInMemorySigner(pkey, hashAlgo)
+		})
+		defer RegisterKMSProvider(config.KeyTypeAWSKMS, nil)
+
+		key, err := keyFromConfig(awsConf)
+		assert.NoError(t, err)
+
+		ready, err := key.ReadyToSign(context.Background())
+		assert.True(t, ready)
+		assert.NoError(t, err)
+
+		signer, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.IsType(t, AWSKMSKey{}, received)
+		assert.Equal(t, pkey.PublicKey().String(), signer.PublicKey().String())
+
+		message := []byte("message")
+		sig, err := signer.Sign(message)
+		assert.NoError(t, err)
+		valid, err := pkey.PublicKey().Verify(sig, message, crypto.NewSHA3_256())
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+}
+
+func Test_NewRotatedKMSKey(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	old, err := keyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeKMS,
+		Index:      1,
+		Weight:     500,
+		ResourceID: "arn:aws:kms:us-east-1:111122223333:key/old",
+	})
+	assert.NoError(t, err)
+
+	t.Run("Rotate", func(t *testing.T) {
+		RegisterKMSProvider(config.KeyTypeAWSKMS, func(_ context.Context, _ any, hashAlgo crypto.HashAlgorithm) (crypto.Signer, error) {
+			return crypto.NewInMemorySigner(pkey, hashAlgo)
+		})
+		defer RegisterKMSProvider(config.KeyTypeAWSKMS, nil)
+
+		key, err := NewRotatedKMSKey(context.Background(), old, "arn:aws:kms:us-east-1:111122223333:key/new", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, key.Index())
+		assert.Equal(t, 500, key.Weight())
+		assert.Equal(t, "arn:aws:kms:us-east-1:111122223333:key/new", key.ToConfig().ResourceID)
+	})
+
+	t.Run("Fail same resource", func(t *testing.T) {
+		_, err := NewRotatedKMSKey(context.Background(), old, "arn:aws:kms:us-east-1:111122223333:key/old", 2)
+		assert.EqualError(t, err, "new KMS resource ID is the same as the old one")
+	})
+
+	t.Run("Fail same index", func(t *testing.T) {
+		_, err := NewRotatedKMSKey(context.Background(), old, "arn:aws:kms:us-east-1:111122223333:key/new", 1)
+		assert.EqualError(t, err, "invalid index 1 of the new KMS key, must be the next free index of the account")
+	})
+
+	t.Run("Fail not ready", func(t *testing.T) {
+		_, err := NewRotatedKMSKey(context.Background(), old, "arn:aws:kms:us-east-1:111122223333:key/new", 2)
+		assert.EqualError(t, err, "new KMS key arn:aws:kms:us-east-1:111122223333:key/new is not accessible: aws-kms keys are not supported, register a signer using RegisterKMSProvider")
+	})
+
+	t.Run("Fail not KMS key", func(t *testing.T) {
+		_, err := NewRotatedKMSKey(context.Background(), NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey), "arn:aws:kms:us-east-1:111122223333:key/new", 1)
+		assert.EqualError(t, err, "can not rotate hex key, only KMS keys can be rotated")
+	})
+}
@@ -0,0 +1,229 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_RemoteHTTPKey(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	message := []byte("message")
+	digest := crypto.NewSHA3_256().ComputeHash(message)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("invalid token"))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/keys/alice":
+			_ = json.NewEncoder(w).Encode(map[string]string{"publicKey": pkey.PublicKey().String()})
+		case "/keys/alice/sign":
+			var req map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, hex.EncodeToString(digest), req["digest"])
+			assert.Equal(t, "SHA3_256", req["hashAlgorithm"])
+			sig, _ := pkey.Sign(message, crypto.NewSHA3_256())
+			_ = json.NewEncoder(w).Encode(map[string]string{"signature": hex.EncodeToString(sig)})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("REMOTE_SIGNER_TOKEN", "secret")
+	confKey := config.AccountKey{
+		Type:               config.KeyTypeRemoteHTTP,
+		SigAlgo:            crypto.ECDSA_P256,
+		HashAlgo:           crypto.SHA3_256,
+		ResourceID:         "alice",
+		Endpoint:           server.URL,
+		AuthToken:          "$REMOTE_SIGNER_TOKEN",
+		InsecureSkipVerify: true,
+	}
+
+	t.Run("Sign", func(t *testing.T) {
+		key, err := keyFromConfig(confKey)
+		assert.NoError(t, err)
+		assert.Equal(t, confKey, key.ToConfig())
+
+		signer, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, pkey.PublicKey().String(), signer.PublicKey().String())
+
+		sig, err := signer.Sign(message)
+		assert.NoError(t, err)
+		valid, err := pkey.PublicKey().Verify(sig, message, crypto.NewSHA3_256())
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("Fail TLS verification", func(t *testing.T) {
+		conf := confKey
+		conf.InsecureSkipVerify = false
+		key, err := keyFromConfig(conf)
+		assert.NoError(t, err)
+
+		_, err = key.Signer(context.Background())
+		assert.ErrorContains(t, err, "remote signer request failed")
+	})
+
+	t.Run("Fail status", func(t *testing.T) {
+		conf := confKey
+		conf.AuthToken = "wrong"
+		key, err := keyFromConfig(conf)
+		assert.NoError(t, err)
+
+		_, err = key.Signer(context.Background())
+		assert.EqualError(t, err, "remote signer returned status 401: invalid token")
+	})
+
+	t.Run("Fail cancelled", func(t *testing.T) {
+		key, err := keyFromConfig(confKey)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = key.Signer(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Fail invalid config", func(t *testing.T) {
+		conf := confKey
+		conf.Endpoint = "invalid"
+		_, err := keyFromConfig(conf)
+		assert.EqualError(t, err, "invalid remote signer endpoint invalid")
+	})
+}
+
+func Test_RemoteHTTPKeySignatureFormat(t *testing.T) {
+	message := []byte("message")
+
+	for _, sigAlgo := range []crypto.SignatureAlgorithm{crypto.ECDSA_P256, crypto.ECDSA_secp256k1} {
+		pkey := tests.DecodePrivKey(sigAlgo, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+		der := false
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/keys/alice" {
+				_ = json.NewEncoder(w).Encode(map[string]string{"publicKey": pkey.PublicKey().String()})
+				return
+			}
+
+			sig, _ := pkey.Sign(message, crypto.NewSHA3_256())
+			if der {
+				sig, _ = asn1.Marshal(ecdsaSignatureDER{
+					R: new(big.Int).SetBytes(sig[:32]),
+					S: new(big.Int).SetBytes(sig[32:]),
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"signature": hex.EncodeToString(sig)})
+		}))
+
+		sign := func(format config.SignatureFormat) ([]byte, error) {
+			key, err := keyFromConfig(config.AccountKey{
+				Type:               config.KeyTypeRemoteHTTP,
+				SigAlgo:            sigAlgo,
+				HashAlgo:           crypto.SHA3_256,
+				ResourceID:         "alice",
+				Endpoint:           server.URL,
+				InsecureSkipVerify: true,
+				SignatureFormat:    format,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, format, key.ToConfig().SignatureFormat)
+
+			signer, err := key.Signer(context.Background())
+			assert.NoError(t, err)
+			return signer.Sign(message)
+		}
+
+		verify := func(sig []byte) {
+			assert.Len(t, sig, 64)
+			valid, err := pkey.PublicKey().Verify(sig, message, crypto.NewSHA3_256())
+			assert.NoError(t, err)
+			assert.True(t, valid)
+		}
+
+		t.Run(fmt.Sprintf("Raw %s", sigAlgo), func(t *testing.T) {
+			der = false
+			for _, format := range []config.SignatureFormat{config.SignatureFormatAuto, config.SignatureFormatRaw} {
+				sig, err := sign(format)
+				assert.NoError(t, err)
+				verify(sig)
+			}
+
+			_, err := sign(config.SignatureFormatDER)
+			assert.ErrorContains(t, err, "remote signer returned invalid signature: invalid DER signature")
+		})
+
+		t.Run(fmt.Sprintf("DER %s", sigAlgo), func(t *testing.T) {
+			der = true
+			for _, format := range []config.SignatureFormat{config.SignatureFormatAuto, config.SignatureFormatDER} {
+				sig, err := sign(format)
+				assert.NoError(t, err)
+				verify(sig)
+			}
+
+			_, err := sign(config.SignatureFormatRaw)
+			assert.ErrorContains(t, err, "remote signer returned invalid signature: raw signature must be 64 bytes")
+		})
+
+		server.Close()
+	}
+}
+
+func Test_DecodeECDSASignature(t *testing.T) {
+	// short scalars are encoded with fewer bytes in DER and padded in the raw format
+	der, err := asn1.Marshal(ecdsaSignatureDER{R: big.NewInt(1), S: big.NewInt(258)})
+	assert.NoError(t, err)
+	assert.Len(t, der, 9)
+
+	raw, err := decodeECDSASignature(der, config.SignatureFormatAuto)
+	assert.NoError(t, err)
+	expected := make([]byte, 64)
+	expected[31], expected[62], expected[63] = 1, 1, 2
+	assert.Equal(t, expected, raw)
+
+	raw, err = decodeECDSASignature(expected, config.SignatureFormatAuto)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, raw)
+
+	_, err = decodeECDSASignature(append(der, 0), config.SignatureFormatAuto)
+	assert.EqualError(t, err, "signature of 10 bytes is neither a raw nor a DER signature")
+
+	_, err = decodeECDSASignature(append(der, 0), config.SignatureFormatDER)
+	assert.EqualError(t, err, "invalid DER signature: 1 trailing bytes")
+}
@@ -0,0 +1,138 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// AWSKMSKey is a reference to an AWS KMS key parsed from the key ARN.
+//
+// Ref: https://docs.aws.amazon.com/kms/latest/developerguide/concepts.html#key-id-key-ARN
+type AWSKMSKey struct {
+	Partition string
+	Region    string
+	AccountID string
+	KeyID     string
+}
+
+// ResourceID returns the ARN of the key.
+func (k AWSKMSKey) ResourceID() string {
+	return fmt.Sprintf("arn:%s:kms:%s:%s:key/%s", k.Partition, k.Region, k.AccountID, k.KeyID)
+}
+
+// AzureKMSKey is a reference to an Azure Key Vault key parsed from the key identifier URI.
+//
+// Ref: https://learn.microsoft.com/en-us/azure/key-vault/general/about-keys-secrets-certificates#object-identifiers
+type AzureKMSKey struct {
+	VaultURL string
+	Name     string
+	Version  string
+}
+
+// ResourceID returns the key identifier URI.
+func (k AzureKMSKey) ResourceID() string {
+	id := fmt.Sprintf("%s/keys/%s", k.VaultURL, k.Name)
+	if k.Version != "" {
+		id = fmt.Sprintf("%s/%s", id, k.Version)
+	}
+	return id
+}
+
+// ParseResourceID detects the KMS provider from the resource ID and parses it.
+//
+// Supported formats are Google Cloud KMS key version paths parsed to cloudkms.Key,
+// AWS KMS key ARNs parsed to AWSKMSKey and Azure Key Vault key URIs parsed to AzureKMSKey.
+func ParseResourceID(raw string) (config.KeyType, any, error) {
+	raw = strings.TrimSpace(raw)
+
+	switch {
+	case strings.HasPrefix(raw, "projects/"):
+		key, err := cloudkms.KeyFromResourceID(raw)
+		if err != nil {
+			return "", nil, err
+		}
+		return config.KeyTypeGoogleKMS, key, nil
+
+	case strings.HasPrefix(raw, "arn:"):
+		key, err := parseAWSKMSKey(raw)
+		if err != nil {
+			return "", nil, err
+		}
+		return config.KeyTypeAWSKMS, key, nil
+
+	case strings.HasPrefix(raw, "https://"):
+		key, err := parseAzureKMSKey(raw)
+		if err != nil {
+			return "", nil, err
+		}
+		return config.KeyTypeAzureKMS, key, nil
+	}
+
+	return "", nil, fmt.Errorf("could not detect KMS provider from resource ID %s", raw)
+}
+
+// parseAWSKMSKey parses the key ARN in the format arn:<partition>:kms:<region>:<account>:key/<key-id>.
+func parseAWSKMSKey(raw string) (AWSKMSKey, error) {
+	parts := strings.SplitN(raw, ":", 6)
+	if len(parts) != 6 || parts[2] != "kms" || !strings.HasPrefix(parts[5], "key/") {
+		return AWSKMSKey{}, fmt.Errorf("invalid AWS KMS key ARN %s", raw)
+	}
+
+	key := AWSKMSKey{
+		Partition: parts[1],
+		Region:    parts[3],
+		AccountID: parts[4],
+		KeyID:     strings.TrimPrefix(parts[5], "key/"),
+	}
+	if key.Partition == "" || key.Region == "" || key.AccountID == "" || key.KeyID == "" {
+		return AWSKMSKey{}, fmt.Errorf("invalid AWS KMS key ARN %s", raw)
+	}
+
+	return key, nil
+}
+
+// parseAzureKMSKey parses the key URI in the format https://<vault>.vault.azure.net/keys/<name>[/<version>].
+func parseAzureKMSKey(raw string) (AzureKMSKey, error) {
+	u, err := url.Parse(raw)
+	if err != nil || !strings.HasSuffix(u.Host, ".vault.azure.net") {
+		return AzureKMSKey{}, fmt.Errorf("invalid Azure Key Vault key URI %s", raw)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "keys" || parts[1] == "" {
+		return AzureKMSKey{}, fmt.Errorf("invalid Azure Key Vault key URI %s", raw)
+	}
+
+	key := AzureKMSKey{
+		VaultURL: fmt.Sprintf("https://%s", u.Host),
+		Name:     parts[1],
+	}
+	if len(parts) == 3 {
+		key.Version = parts[2]
+	}
+
+	return key, nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto/cloudkms"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+func Test_ParseResourceID(t *testing.T) {
+	t.Run("Google KMS", func(t *testing.T) {
+		const id = "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1"
+		provider, parsed, err := ParseResourceID(id)
+		assert.NoError(t, err)
+		assert.Equal(t, config.KeyTypeGoogleKMS, provider)
+		assert.Equal(t, "my-project", parsed.(cloudkms.Key).ProjectID)
+		assert.Equal(t, id, parsed.(cloudkms.Key).ResourceID())
+	})
+
+	t.Run("AWS KMS", func(t *testing.T) {
+		const id = "arn:aws:kms:us-east-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+		provider, parsed, err := ParseResourceID(id)
+		assert.NoError(t, err)
+		assert.Equal(t, config.KeyTypeAWSKMS, provider)
+		assert.Equal(t, AWSKMSKey{
+			Partition: "aws",
+			Region:    "us-east-1",
+			AccountID: "111122223333",
+			KeyID:     "1234abcd-12ab-34cd-56ef-1234567890ab",
+		}, parsed)
+		assert.Equal(t, id, parsed.(AWSKMSKey).ResourceID())
+	})
+
+	t.Run("Azure KMS", func(t *testing.T) {
+		const id = "https://my-vault.vault.azure.net/keys/my-account/78deebed173b48e48f55abf87ed4cf71"
+		provider, parsed, err := ParseResourceID(id)
+		assert.NoError(t, err)
+		assert.Equal(t, config.KeyTypeAzureKMS, provider)
+		assert.Equal(t, AzureKMSKey{
+			VaultURL: "https://my-vault.vault.azure.net",
+			Name:     "my-account",
+			Version:  "78deebed173b48e48f55abf87ed4cf71",
+		}, parsed)
+		assert.Equal(t, id, parsed.(AzureKMSKey).ResourceID())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := []string{
+			"",
+			"projects/my-project",
+			"arn:aws:s3:::my-bucket",
+			"arn:aws:kms:us-east-1:111122223333:alias/my-key",
+			"https://example.com/keys/my-account",
+			"https://my-vault.vault.azure.net/secrets/my-account",
+		}
+		for _, id := range invalid {
+			_, _, err := ParseResourceID(id)
+			assert.Error(t, err, id)
+		}
+	})
+}
+
+func Test_KMSKeyFromConfig(t *testing.T) {
+	key, err := keyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeKMS,
+		ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+	})
+	assert.NoError(t, err)
+	assert.IsType(t, &RemoteKMSKey{}, key)
+	assert.Equal(t, config.KeyTypeKMS, key.Type())
+	assert.Equal(t, config.KeyTypeGoogleKMS, key.(*RemoteKMSKey).Provider())
+
+	_, err = keyFromConfig(config.AccountKey{
+		Type:       config.KeyTypeKMS,
+		ResourceID: "invalid",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/gateway"
+)
+
+// DiscoveredKey is a key derived from a mnemonic which is registered on an account on the network.
+type DiscoveredKey struct {
+	Address        flow.Address
+	DerivationPath string
+	// AccountKey is the key registered on the network, including its index, weight and revocation status.
+	AccountKey *flow.AccountKey
+	// Key is the derived key configured with the index and the algorithms of the registered key.
+	Key *BIP44Key
+}
+
+// ScanMnemonicOnChain derives the keys of the mnemonic at the derivation paths "m/44'/539'/0'/0/<index>" up to
+// and including maxIndex for all the signature algorithms supported by BIP44 keys, and returns the derived keys
+// registered on the accounts, which supports recovering a wallet or auditing the use of a mnemonic.
+//
+// The access API can't look up accounts by public key, so the addresses of the accounts that might hold
+// the keys must be provided. The keys are returned ordered by the address and the key index on the account.
+func ScanMnemonicOnChain(
+	ctx context.Context,
+	mnemonic string,
+	gw gateway.Gateway,
+	maxIndex int,
+	addresses ...flow.Address,
+) ([]DiscoveredKey, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no account addresses to scan, the network can't be searched by public key")
+	}
+	if maxIndex < 0 {
+		return nil, fmt.Errorf("invalid maximum derivation index %d", maxIndex)
+	}
+
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+
+	type derivation struct {
+		path    string
+		sigAlgo crypto.SignatureAlgorithm
+	}
+	derived := make(map[string]derivation) // by the encoded public key
+	for index := 0; index <= maxIndex; index++ {
+		path := fmt.Sprintf("m/44'/539'/0'/0/%d", index)
+		for _, sigAlgo := range []crypto.SignatureAlgorithm{crypto.ECDSA_P256, crypto.ECDSA_secp256k1} {
+			key, err := bip44KeyFromConfig(config.AccountKey{
+				Type:           config.KeyTypeBip44,
+				SigAlgo:        sigAlgo,
+				Mnemonic:       mnemonic,
+				DerivationPath: path,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			publicKey, err := keyPublicKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to derive the %s key at path %s: %w", sigAlgo, path, err)
+			}
+			derived[string(publicKey.Encode())] = derivation{path: path, sigAlgo: sigAlgo}
+		}
+	}
+
+	discovered := make([]DiscoveredKey, 0)
+	for _, address := range addresses {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		account, err := gw.GetAccount(address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", address, err)
+		}
+
+		for _, accountKey := range account.Keys {
+			d, ok := derived[string(accountKey.PublicKey.Encode())]
+			if !ok || accountKey.SigAlgo != d.sigAlgo {
+				continue
+			}
+
+			key, err := bip44KeyFromConfig(config.AccountKey{
+				Type:           config.KeyTypeBip44,
+				Index:          accountKey.Index,
+				Weight:         accountKey.Weight,
+				WeightSet:      true, // a zero weight on the network is not the default full weight
+				SigAlgo:        accountKey.SigAlgo,
+				HashAlgo:       accountKey.HashAlgo,
+				Mnemonic:       mnemonic,
+				DerivationPath: d.path,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			discovered = append(discovered, DiscoveredKey{
+				Address:        address,
+				DerivationPath: d.path,
+				AccountKey:     accountKey,
+				Key:            key.(*BIP44Key),
+			})
+		}
+	}
+
+	return discovered, nil
+}
@@ -0,0 +1,115 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/gateway/mocks"
+)
+
+func Test_ScanMnemonicOnChain(t *testing.T) {
+	const mnemonic = "version field tornado move level pretty inject stereo ten catalog salon swallow"
+
+	derive := func(sigAlgo crypto.SignatureAlgorithm, index int) crypto.PublicKey {
+		key, err := bip44KeyFromConfig(config.AccountKey{
+			Type:           config.KeyTypeBip44,
+			SigAlgo:        sigAlgo,
+			Mnemonic:       mnemonic,
+			DerivationPath: fmt.Sprintf("m/44'/539'/0'/0/%d", index),
+		})
+		assert.NoError(t, err)
+		publicKey, err := keyPublicKey(key)
+		assert.NoError(t, err)
+		return publicKey
+	}
+
+	other, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+
+	alice := flow.HexToAddress("01")
+	bob := flow.HexToAddress("02")
+	gw := &mocks.Gateway{}
+	gw.On(mocks.GetAccountFunc, alice).Return(&flow.Account{
+		Address: alice,
+		Keys: []*flow.AccountKey{
+			{Index: 0, PublicKey: other.PublicKey(), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256, Weight: 1000},
+			{Index: 1, PublicKey: derive(crypto.ECDSA_P256, 2), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256, Weight: 500},
+		},
+	}, nil)
+	gw.On(mocks.GetAccountFunc, bob).Return(&flow.Account{
+		Address: bob,
+		Keys: []*flow.AccountKey{
+			{Index: 0, PublicKey: derive(crypto.ECDSA_secp256k1, 0), SigAlgo: crypto.ECDSA_secp256k1, HashAlgo: crypto.SHA2_256, Weight: 1000},
+			{Index: 1, PublicKey: derive(crypto.ECDSA_P256, 5), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256, Weight: 1000},
+		},
+	}, nil)
+
+	discovered, err := ScanMnemonicOnChain(context.Background(), mnemonic, gw, 3, alice, bob)
+	assert.NoError(t, err)
+	assert.Len(t, discovered, 2)
+
+	assert.Equal(t, alice, discovered[0].Address)
+	assert.Equal(t, "m/44'/539'/0'/0/2", discovered[0].DerivationPath)
+	assert.Equal(t, 1, discovered[0].AccountKey.Index)
+	assert.Equal(t, 1, discovered[0].Key.Index())
+	assert.Equal(t, 500, discovered[0].Key.Weight())
+
+	assert.Equal(t, bob, discovered[1].Address)
+	assert.Equal(t, "m/44'/539'/0'/0/0", discovered[1].DerivationPath)
+	assert.Equal(t, crypto.ECDSA_secp256k1, discovered[1].Key.SigAlgo())
+	assert.Equal(t, crypto.SHA2_256, discovered[1].Key.HashAlgo())
+
+	// the discovered key signs for the registered key
+	signer, err := discovered[1].Key.Signer(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, signer.PublicKey().Equals(discovered[1].AccountKey.PublicKey))
+
+	t.Run("Zero weight", func(t *testing.T) {
+		charlie := flow.HexToAddress("04")
+		gw.On(mocks.GetAccountFunc, charlie).Return(&flow.Account{
+			Address: charlie,
+			Keys: []*flow.AccountKey{
+				{Index: 0, PublicKey: derive(crypto.ECDSA_P256, 1), SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256, Weight: 0, Revoked: true},
+			},
+		}, nil)
+
+		discovered, err := ScanMnemonicOnChain(context.Background(), mnemonic, gw, 3, charlie)
+		assert.NoError(t, err)
+		assert.Len(t, discovered, 1)
+		assert.Equal(t, 0, discovered[0].Key.Weight())
+	})
+
+	_, err = ScanMnemonicOnChain(context.Background(), mnemonic, gw, 3)
+	assert.EqualError(t, err, "no account addresses to scan, the network can't be searched by public key")
+
+	_, err = ScanMnemonicOnChain(context.Background(), "not a mnemonic", gw, 3, alice)
+	assert.EqualError(t, err, "invalid mnemonic")
+
+	gw.On(mocks.GetAccountFunc, flow.HexToAddress("03")).Return(nil, fmt.Errorf("account not found"))
+	_, err = ScanMnemonicOnChain(context.Background(), mnemonic, gw, 0, flow.HexToAddress("03"))
+	assert.EqualError(t, err, "failed to get account 0000000000000003: account not found")
+}
@@ -0,0 +1,187 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// keySharesVersion is the version of the key share format.
+//
+// A share contains the version, the signature and hash algorithms, the key index, the share
+// x coordinate and the share of the private key followed by its checksum.
+const keySharesVersion = 1
+
+const keyShareHeaderLength = 8
+
+// keyShareChecksumLength is the length of the private key checksum used to detect invalid or too few shares.
+const keyShareChecksumLength = 4
+
+// SplitKeyShares splits the private key into n Shamir secret shares, any threshold of which recombine the key.
+//
+// Only keys with an accessible private key can be split, fewer shares than the threshold reveal nothing about the key.
+func SplitKeyShares(key Key, n int, threshold int) ([][]byte, error) {
+	if threshold < 2 || threshold > n {
+		return nil, fmt.Errorf("threshold must be between 2 and the number of shares %d, got %d", n, threshold)
+	}
+	if n > 255 {
+		return nil, fmt.Errorf("at most 255 shares can be created, got %d", n)
+	}
+
+	pkey, err := key.PrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("key shares can only be created for keys with an accessible private key: %w", err)
+	}
+
+	encoded := (*pkey).Encode()
+	checksum := sha256.Sum256(encoded)
+	secret := append(encoded, checksum[:keyShareChecksumLength]...)
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, keyShareHeaderLength, keyShareHeaderLength+len(secret))
+		shares[i][0] = keySharesVersion
+		shares[i][1] = byte(key.SigAlgo())
+		shares[i][2] = byte(key.HashAlgo())
+		binary.BigEndian.PutUint32(shares[i][3:7], uint32(key.Index()))
+		shares[i][7] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, threshold)
+	for _, b := range secret {
+		_, err := rand.Read(coefficients[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate random coefficients: %w", err)
+		}
+		coefficients[0] = b
+
+		for i := range shares {
+			shares[i] = append(shares[i], gfEvaluate(coefficients, byte(i+1)))
+		}
+	}
+
+	return shares, nil
+}
+
+// RecombineKeyShares recombines the key from the shares created by SplitKeyShares.
+//
+// An error is returned if the shares don't belong to the same key or if there are fewer shares than the threshold.
+func RecombineKeyShares(shares [][]byte) (*HexKey, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no key shares provided")
+	}
+
+	header := shares[0]
+	if len(header) <= keyShareHeaderLength+keyShareChecksumLength || header[0] != keySharesVersion {
+		return nil, fmt.Errorf("invalid key share")
+	}
+
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		if len(share) != len(header) || !bytes.Equal(share[:7], header[:7]) {
+			return nil, fmt.Errorf("key shares don't belong to the same key")
+		}
+
+		xs[i] = share[7]
+		if xs[i] == 0 || bytes.IndexByte(xs[:i], xs[i]) != -1 {
+			return nil, fmt.Errorf("invalid or duplicate key share")
+		}
+	}
+
+	secret := make([]byte, len(header)-keyShareHeaderLength)
+	ys := make([]byte, len(shares))
+	for b := range secret {
+		for i, share := range shares {
+			ys[i] = share[keyShareHeaderLength+b]
+		}
+		secret[b] = gfInterpolate(xs, ys)
+	}
+
+	encoded := secret[:len(secret)-keyShareChecksumLength]
+	checksum := sha256.Sum256(encoded)
+	if !bytes.Equal(checksum[:keyShareChecksumLength], secret[len(encoded):]) {
+		return nil, fmt.Errorf("failed to recombine the key, not enough or invalid key shares")
+	}
+
+	sigAlgo := crypto.SignatureAlgorithm(header[1])
+	pkey, err := crypto.DecodePrivateKey(sigAlgo, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the recombined key: %w", err)
+	}
+
+	index := int(binary.BigEndian.Uint32(header[3:7]))
+	return NewHexKeyFromPrivateKey(index, crypto.HashAlgorithm(header[2]), pkey), nil
+}
+
+// gfMul multiplies in GF(2^8) using the AES reducing polynomial.
+func gfMul(a byte, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 == 1 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns the multiplicative inverse in GF(2^8), computed as a^254.
+func gfInv(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 254; i++ {
+		result = gfMul(result, a)
+	}
+	return result
+}
+
+// gfEvaluate evaluates the polynomial with the coefficients at x.
+func gfEvaluate(coefficients []byte, x byte) byte {
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coefficients[i]
+	}
+	return result
+}
+
+// gfInterpolate returns the value at 0 of the polynomial passing through the points.
+func gfInterpolate(xs []byte, ys []byte) byte {
+	var result byte
+	for i := range xs {
+		basis := byte(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			basis = gfMul(basis, gfMul(xs[j], gfInv(xs[j]^xs[i])))
+		}
+		result ^= gfMul(ys[i], basis)
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+func Test_KeyShares(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_secp256k1, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	key := NewHexKeyFromPrivateKey(3, crypto.SHA2_256, pkey)
+
+	shares, err := SplitKeyShares(key, 5, 3)
+	assert.NoError(t, err)
+	assert.Len(t, shares, 5)
+
+	t.Run("Recombine", func(t *testing.T) {
+		for _, subset := range [][][]byte{
+			{shares[0], shares[1], shares[2]},
+			{shares[4], shares[2], shares[0]},
+			shares,
+		} {
+			recombined, err := RecombineKeyShares(subset)
+			assert.NoError(t, err)
+			assert.Equal(t, key.ToConfig(), recombined.ToConfig())
+		}
+	})
+
+	t.Run("Fail not enough shares", func(t *testing.T) {
+		_, err := RecombineKeyShares(shares[:2])
+		assert.EqualError(t, err, "failed to recombine the key, not enough or invalid key shares")
+	})
+
+	t.Run("Fail mixed shares", func(t *testing.T) {
+		other, err := SplitKeyShares(NewHexKeyFromPrivateKey(0, crypto.SHA2_256, pkey), 3, 2)
+		assert.NoError(t, err)
+
+		_, err = RecombineKeyShares([][]byte{shares[0], other[1]})
+		assert.EqualError(t, err, "key shares don't belong to the same key")
+	})
+
+	t.Run("Fail duplicate shares", func(t *testing.T) {
+		_, err := RecombineKeyShares([][]byte{shares[0], shares[0], shares[1]})
+		assert.EqualError(t, err, "invalid or duplicate key share")
+	})
+
+	t.Run("Fail invalid threshold", func(t *testing.T) {
+		_, err := SplitKeyShares(key, 3, 4)
+		assert.EqualError(t, err, "threshold must be between 2 and the number of shares 3, got 4")
+		_, err = SplitKeyShares(key, 3, 1)
+		assert.Error(t, err)
+		_, err = SplitKeyShares(key, 256, 2)
+		assert.EqualError(t, err, "at most 255 shares can be created, got 256")
+	})
+
+	t.Run("Fail remote key", func(t *testing.T) {
+		kmsKey, err := kmsKeyFromConfig(config.AccountKey{
+			Type:       config.KeyTypeGoogleKMS,
+			ResourceID: "projects/my-project/locations/global/keyRings/flow/cryptoKeys/my-account/cryptoKeyVersions/1",
+		})
+		assert.NoError(t, err)
+
+		_, err = SplitKeyShares(kmsKey, 3, 2)
+		assert.ErrorContains(t, err, "key shares can only be created for keys with an accessible private key")
+	})
+}
+
+func Test_GFInv(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), gfMul(byte(a), gfInv(byte(a))))
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// secp256k1Order is the order of the secp256k1 curve group.
+var secp256k1Order, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+var secp256k1HalfOrder = new(big.Int).Rsh(secp256k1Order, 1)
+
+// canonicalSigner wraps the signer so secp256k1 signatures are always produced in the low-S form.
+//
+// ECDSA signatures (r, s) and (r, n-s) are both valid, but some strict verifiers only accept
+// the variant where s is in the lower half of the curve order.
+func canonicalSigner(sigAlgo crypto.SignatureAlgorithm, signer crypto.Signer) crypto.Signer {
+	if sigAlgo != crypto.ECDSA_secp256k1 {
+		return signer
+	}
+	return &lowSSigner{signer}
+}
+
+type lowSSigner struct {
+	crypto.Signer
+}
+
+func (s *lowSSigner) Sign(message []byte) ([]byte, error) {
+	sig, err := s.Signer.Sign(message)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeLowS(sig), nil
+}
+
+// normalizeLowS converts the secp256k1 signature in the r || s format to the low-S form.
+func normalizeLowS(sig []byte) []byte {
+	if len(sig) != 64 {
+		return sig
+	}
+
+	s := new(big.Int).SetBytes(sig[32:])
+	if s.Cmp(secp256k1HalfOrder) <= 0 {
+		return sig
+	}
+
+	normalized := make([]byte, 64)
+	copy(normalized, sig[:32])
+	s.Sub(secp256k1Order, s).FillBytes(normalized[32:])
+	return normalized
+}
+
+// ecdsaScalarSize is the size of the signature scalars of the curves supported by Flow, P-256 and secp256k1.
+const ecdsaScalarSize = 32
+
+// ecdsaSignatureDER is the ASN.1 structure of DER encoded ECDSA signatures.
+type ecdsaSignatureDER struct {
+	R, S *big.Int
+}
+
+// decodeECDSASignature converts the ECDSA signature in the format to the raw r || s format used by Flow.
+//
+// If the format is not set it is detected, a signature that is a well-formed DER sequence is decoded
+// as DER, otherwise it must be a raw signature.
+func decodeECDSASignature(sig []byte, format config.SignatureFormat) ([]byte, error) {
+	switch format {
+	case config.SignatureFormatRaw:
+		if len(sig) != 2*ecdsaScalarSize {
+			return nil, fmt.Errorf("raw signature must be %d bytes, got %d", 2*ecdsaScalarSize, len(sig))
+		}
+		return sig, nil
+	case config.SignatureFormatDER:
+		return decodeDERSignature(sig)
+	}
+
+	if raw, err := decodeDERSignature(sig); err == nil {
+		return raw, nil
+	}
+	if len(sig) != 2*ecdsaScalarSize {
+		return nil, fmt.Errorf("signature of %d bytes is neither a raw nor a DER signature", len(sig))
+	}
+	return sig, nil
+}
+
+// decodeDERSignature converts the DER encoded ECDSA signature to the raw r || s format.
+func decodeDERSignature(sig []byte) ([]byte, error) {
+	var parsed ecdsaSignatureDER
+	rest, err := asn1.Unmarshal(sig, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DER signature: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("invalid DER signature: %d trailing bytes", len(rest))
+	}
+
+	if parsed.R.Sign() <= 0 || parsed.S.Sign() <= 0 ||
+		parsed.R.BitLen() > 8*ecdsaScalarSize || parsed.S.BitLen() > 8*ecdsaScalarSize {
+		return nil, fmt.Errorf("invalid DER signature: scalars out of range")
+	}
+
+	raw := make([]byte, 2*ecdsaScalarSize)
+	parsed.R.FillBytes(raw[:ecdsaScalarSize])
+	parsed.S.FillBytes(raw[ecdsaScalarSize:])
+	return raw, nil
+}
+
+// newInMemorySigner creates a signer for the private key, the signer is deterministic in the deterministic signing mode.
+func newInMemorySigner(privateKey crypto.PrivateKey, hashAlgo crypto.HashAlgorithm) (crypto.Signer, error) {
+	if deterministicSigning {
+		signer, err := NewDeterministicSigner(privateKey, hashAlgo)
+		if err != nil {
+			return nil, err
+		}
+		return canonicalSigner(privateKey.Algorithm(), signer), nil
+	}
+
+	signer, err := crypto.NewInMemorySigner(privateKey, hashAlgo)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalSigner(privateKey.Algorithm(), signer), nil
+}
+
+// DomainTag separates signatures of different kinds of messages, it is encoded as UTF-8 bytes right padded to 32 bytes.
+type DomainTag [32]byte
+
+var (
+	// TransactionDomainTag is the tag of transaction signatures, transactions are always signed using it.
+	TransactionDomainTag = DomainTag(flow.TransactionDomainTag)
+	// UserDomainTag is the tag of user messages which can be verified in Cadence.
+	UserDomainTag = DomainTag(flow.UserDomainTag)
+)
+
+// NewDomainTag creates a domain tag padding the tag, which can be at most 32 bytes long.
+func NewDomainTag(tag string) (DomainTag, error) {
+	var domainTag DomainTag
+	if len(tag) > len(domainTag) {
+		return domainTag, fmt.Errorf("domain tag %s can not be longer than %d bytes", tag, len(domainTag))
+	}
+	copy(domainTag[:], tag)
+	return domainTag, nil
+}
+
+type domainTagSigner struct {
+	crypto.Signer
+	tag DomainTag
+}
+
+// NewDomainTagSigner wraps the signer so every message is prefixed with the domain tag before it's signed.
+func NewDomainTagSigner(signer crypto.Signer, tag DomainTag) crypto.Signer {
+	return &domainTagSigner{
+		Signer: signer,
+		tag:    tag,
+	}
+}
+
+func (s *domainTagSigner) Sign(message []byte) ([]byte, error) {
+	return s.Signer.Sign(append(s.tag[:], message...))
+}
+
+// SignMessage signs the message prefixed with the domain tag using the key.
+func SignMessage(ctx context.Context, key Key, message []byte, tag DomainTag) ([]byte, error) {
+	if !key.SupportsMessageSigning() {
+		return nil, fmt.Errorf("key does not support signing messages")
+	}
+
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDomainTagSigner(signer, tag).Sign(message)
+}
+
+// VerifyTransactionSignatures verifies every payload and envelope signature of the transaction using the
+// public key and the hash algorithm of the local key that signed it, which catches wrongly assembled
+// transactions before they are sent and rejected by the network.
+//
+// The signing key is found by the address and the key index of the signature among the accounts,
+// including the key indices used for specific roles. An error lists all the invalid signatures.
+func VerifyTransactionSignatures(ctx context.Context, tx *flow.Transaction, accounts []*Account) error {
+	problems := make([]string, 0)
+
+	verify := func(kind string, signatures []flow.TransactionSignature, message []byte) error {
+		message = append(flow.TransactionDomainTag[:], message...)
+
+		for _, sig := range signatures {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			key := signingKey(accounts, sig.Address, sig.KeyIndex)
+			if key == nil {
+				problems = append(problems, fmt.Sprintf(
+					"no local key for the %s signature of account %s with key index %d", kind, sig.Address, sig.KeyIndex,
+				))
+				continue
+			}
+
+			publicKey, err := keyPublicKeyCtx(ctx, key)
+			if err != nil {
+				return fmt.Errorf("could not get the public key of account %s with key index %d: %w", sig.Address, sig.KeyIndex, err)
+			}
+
+			valid, err := verifySignature(publicKey, key.HashAlgo(), sig.Signature, message)
+			if err != nil || !valid {
+				problems = append(problems, fmt.Sprintf(
+					"%s signature of account %s with key index %d does not verify", kind, sig.Address, sig.KeyIndex,
+				))
+			}
+		}
+		return nil
+	}
+
+	if err := verify("payload", tx.PayloadSignatures, tx.PayloadMessage()); err != nil {
+		return err
+	}
+	if err := verify("envelope", tx.EnvelopeSignatures, tx.EnvelopeMessage()); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid transaction signatures: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// signingKey returns the key of the account with the address which signs using the key index.
+func signingKey(accounts []*Account, address flow.Address, keyIndex int) Key {
+	for _, acc := range accounts {
+		if acc == nil || acc.Key == nil || acc.Address != address {
+			continue
+		}
+		if acc.Key.Index() == keyIndex {
+			return acc.Key
+		}
+		for _, index := range acc.RoleKeyIndices {
+			if index == keyIndex {
+				return acc.Key
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_LowSSignatures(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_secp256k1, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	publicKey := pkey.PublicKey()
+
+	hasher, err := crypto.NewHasher(crypto.SHA3_256)
+	assert.NoError(t, err)
+
+	t.Run("Normalize", func(t *testing.T) {
+		inner, err := crypto.NewInMemorySigner(pkey, crypto.SHA3_256)
+		assert.NoError(t, err)
+		message := []byte("message")
+
+		sig, err := inner.Sign(message)
+		assert.NoError(t, err)
+
+		// flip the signature to the other form, both are valid
+		s := new(big.Int).SetBytes(sig[32:])
+		flipped := make([]byte, 64)
+		copy(flipped, sig[:32])
+		new(big.Int).Sub(secp256k1Order, s).FillBytes(flipped[32:])
+
+		valid, err := publicKey.Verify(flipped, message, hasher)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		low, high := sig, flipped
+		if s.Cmp(secp256k1HalfOrder) > 0 {
+			low, high = flipped, sig
+		}
+		assert.Equal(t, low, normalizeLowS(high))
+		assert.Equal(t, low, normalizeLowS(low))
+	})
+
+	t.Run("Signer", func(t *testing.T) {
+		key := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+		signer, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			message := []byte{byte(i)}
+			sig, err := signer.Sign(message)
+			assert.NoError(t, err)
+
+			s := new(big.Int).SetBytes(sig[32:])
+			assert.True(t, s.Cmp(secp256k1HalfOrder) <= 0)
+
+			valid, err := publicKey.Verify(sig, message, hasher)
+			assert.NoError(t, err)
+			assert.True(t, valid)
+		}
+	})
+
+	t.Run("Other algorithms", func(t *testing.T) {
+		inner, err := crypto.NewInMemorySigner(pkey, crypto.SHA3_256)
+		assert.NoError(t, err)
+		assert.Equal(t, inner, canonicalSigner(crypto.ECDSA_P256, inner))
+	})
+}
+
+func Test_SignMessage(t *testing.T) {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	assert.NoError(t, err)
+	publicKey := pkey.PublicKey()
+	key := NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey)
+	message := []byte("message")
+
+	custom, err := NewDomainTag("MY-APP-V1")
+	assert.NoError(t, err)
+
+	for _, tag := range []DomainTag{UserDomainTag, TransactionDomainTag, custom} {
+		sig, err := SignMessage(context.Background(), key, message, tag)
+		assert.NoError(t, err)
+
+		valid, err := publicKey.Verify(sig, append(tag[:], message...), crypto.NewSHA3_256())
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	}
+
+	_, err = NewDomainTag("A-DOMAIN-TAG-LONGER-THAN-32-BYTES")
+	assert.EqualError(t, err, "domain tag A-DOMAIN-TAG-LONGER-THAN-32-BYTES can not be longer than 32 bytes")
+}
+
+func Test_VerifyTransactionSignatures(t *testing.T) {
+	aliceKey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+	bobKey := tests.DecodePrivKey(crypto.ECDSA_secp256k1, "68ee617d9bf67a4677af80aaca5a090fcda80ff2f4dbc340e0e36201fa1f1d8c")
+
+	alice := &Account{
+		Name:           "alice",
+		Address:        flow.HexToAddress("0x01"),
+		Key:            NewHexKeyFromPrivateKey(0, crypto.SHA3_256, aliceKey),
+		RoleKeyIndices: map[SignRole]int{SignRoleProposer: 2},
+	}
+	bob := &Account{
+		Name:    "bob",
+		Address: flow.HexToAddress("0x02"),
+		Key:     NewHexKeyFromPrivateKey(1, crypto.SHA2_256, bobKey),
+	}
+
+	newSignedTx := func() *flow.Transaction {
+		tx := flow.NewTransaction().
+			SetScript([]byte(`transaction {}`)).
+			SetProposalKey(alice.Address, 2, 0).
+			SetPayer(bob.Address).
+			AddAuthorizer(alice.Address)
+
+		assert.NoError(t, alice.SignTransaction(context.Background(), tx, SignRoleProposer))
+		assert.NoError(t, alice.SignTransaction(context.Background(), tx, SignRoleAuthorizer))
+		assert.NoError(t, bob.SignTransaction(context.Background(), tx, SignRolePayer))
+		return tx
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		err := VerifyTransactionSignatures(context.Background(), newSignedTx(), []*Account{alice, bob})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fail invalid signature", func(t *testing.T) {
+		tx := newSignedTx()
+		tx.EnvelopeSignatures[0].Signature = tx.PayloadSignatures[0].Signature
+
+		err := VerifyTransactionSignatures(context.Background(), tx, []*Account{alice, bob})
+		assert.EqualError(t, err, "invalid transaction signatures: envelope signature of account 0000000000000002 with key index 1 does not verify")
+	})
+
+	t.Run("Fail modified transaction", func(t *testing.T) {
+		tx := newSignedTx()
+		tx.SetGasLimit(1)
+
+		err := VerifyTransactionSignatures(context.Background(), tx, []*Account{alice, bob})
+		assert.ErrorContains(t, err, "envelope signature of account 0000000000000002 with key index 1 does not verify")
+		assert.ErrorContains(t, err, "payload signature of account 0000000000000001 with key index 2 does not verify")
+	})
+
+	t.Run("Fail missing key", func(t *testing.T) {
+		err := VerifyTransactionSignatures(context.Background(), newSignedTx(), []*Account{alice})
+		assert.EqualError(t, err, "invalid transaction signatures: no local key for the envelope signature of account 0000000000000002 with key index 1")
+	})
+}
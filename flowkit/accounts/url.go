@@ -0,0 +1,208 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"golang.org/x/exp/maps"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// maxURLKeySize limits the size of the key response, a hex encoded private key is much smaller.
+const maxURLKeySize = 1 << 12
+
+// urlKeyClient is the HTTP client used to load keys from a URL.
+var urlKeyClient = http.DefaultClient
+
+var _ Key = &URLKey{}
+
+// URLKey is a key loaded from an HTTPS URL, such as a short-lived signed URL, when it's first used.
+//
+// The URL must return the hex encoded private key. Requests are authenticated with a bearer token
+// and additional headers if provided, the values can reference environment variables.
+// The loaded key is only kept in memory, loading is safe for concurrent use.
+type URLKey struct {
+	*baseKey
+	mu         sync.Mutex
+	privateKey crypto.PrivateKey
+	location   *url.URL
+	authToken  string
+	headers    map[string]string
+}
+
+func urlKeyFromConfig(key config.AccountKey) (*URLKey, error) {
+	location, err := url.Parse(key.Location)
+	if err != nil || location.Host == "" {
+		return nil, fmt.Errorf("invalid key URL")
+	}
+	if location.Scheme != "https" {
+		return nil, fmt.Errorf("key URL %s must use https", redactedURL(location))
+	}
+
+	return &URLKey{
+		baseKey:   baseKeyFromConfig(key),
+		location:  location,
+		authToken: key.AuthToken,
+		headers:   maps.Clone(key.Headers),
+	}, nil
+}
+
+func (u *URLKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := u.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, u)
+	}
+
+	key, err := u.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInMemorySigner(key, u.HashAlgo())
+}
+
+func (u *URLKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(u)
+}
+
+func (u *URLKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(u)
+}
+
+func (u *URLKey) PrivateKey() (*crypto.PrivateKey, error) {
+	key, err := u.load(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Prepare loads the key from the URL.
+func (u *URLKey) Prepare(ctx context.Context) error {
+	_, err := u.load(ctx)
+	return err
+}
+
+// ReadyToSign checks the key was loaded or the configured auth token resolves to a value,
+// reaching the URL requires network access.
+func (u *URLKey) ReadyToSign(_ context.Context) (bool, error) {
+	u.mu.Lock()
+	loaded := u.privateKey != nil
+	u.mu.Unlock()
+
+	if signerProvider != nil || loaded {
+		return true, nil
+	}
+	if u.authToken != "" && os.ExpandEnv(u.authToken) == "" {
+		return false, fmt.Errorf("auth token %s for the key URL is not set", u.authToken)
+	}
+	return true, nil
+}
+
+func (u *URLKey) ToConfig() config.AccountKey {
+	return config.AccountKey{
+		Type:      config.KeyTypeURL,
+		Index:     u.index,
+		Weight:    u.weight,
+		WeightSet: u.weightSet,
+		SigAlgo:   u.sigAlgo,
+		HashAlgo:  u.hashAlgo,
+		Location:  u.location.String(),
+		AuthToken: u.authToken,
+		Headers:   maps.Clone(u.headers),
+	}
+}
+
+func (u *URLKey) String() string {
+	return fmt.Sprintf("URLKey{%s, url:%s}", u.fields(), redactedURL(u.location))
+}
+
+// load fetches and decodes the key from the URL the first time it's called.
+func (u *URLKey) load(ctx context.Context) (crypto.PrivateKey, error) {
+	u.mu.Lock() // the URL is only requested once even if signers are created concurrently
+	defer u.mu.Unlock()
+
+	if u.privateKey != nil {
+		return u.privateKey, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.location.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range u.headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+	if token := os.ExpandEnv(u.authToken); token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	res, err := urlKeyClient.Do(req)
+	if err != nil {
+		// the error includes the URL which can contain a signature in the query
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("could not load the key from %s: %w", redactedURL(u.location), ctx.Err())
+		}
+		return nil, fmt.Errorf("could not load the key from %s", redactedURL(u.location))
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("could not load the key from %s, the server returned status %d", redactedURL(u.location), res.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxURLKeySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not load the key from %s: %w", redactedURL(u.location), err)
+	}
+	if len(data) > maxURLKeySize {
+		return nil, fmt.Errorf("key returned from %s exceeds the maximum size of %d bytes", redactedURL(u.location), maxURLKeySize)
+	}
+
+	pkey, err := config.DecodePrivateKeyHex(u.SigAlgo(), string(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the key from %s: %w", redactedURL(u.location), err)
+	}
+
+	u.privateKey = pkey
+	return pkey, nil
+}
+
+// redactedURL returns the URL without credentials and query, which for signed URLs contains the signature.
+func redactedURL(location *url.URL) string {
+	redacted := *location
+	redacted.User = nil
+	redacted.RawQuery = ""
+	redacted.Fragment = ""
+	return redacted.String()
+}
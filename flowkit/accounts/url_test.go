@@ -0,0 +1,140 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+	"github.com/onflow/flow-cli/flowkit/tests"
+)
+
+func Test_URLKey(t *testing.T) {
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Api-Key") != "api-key" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/key":
+			_, _ = w.Write([]byte(" 0x" + pkey.String()[2:] + "\n"))
+		case "/large":
+			_, _ = w.Write(make([]byte, maxURLKeySize+1))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := urlKeyClient
+	urlKeyClient = server.Client()
+	defer func() { urlKeyClient = client }()
+
+	t.Setenv("KEY_TOKEN", "secret")
+	t.Setenv("KEY_API_KEY", "api-key")
+	confKey := config.AccountKey{
+		Type:      config.KeyTypeURL,
+		SigAlgo:   crypto.ECDSA_P256,
+		HashAlgo:  crypto.SHA3_256,
+		Location:  server.URL + "/key?signature=abc",
+		AuthToken: "$KEY_TOKEN",
+		Headers:   map[string]string{"X-Api-Key": "$KEY_API_KEY"},
+	}
+
+	t.Run("Load", func(t *testing.T) {
+		requests = 0
+		key, err := keyFromConfig(confKey)
+		assert.NoError(t, err)
+		assert.Equal(t, confKey, key.ToConfig())
+		assert.NotContains(t, fmt.Sprint(key), "signature")
+
+		loaded, err := key.PrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, pkey.String(), (*loaded).String())
+
+		_, err = key.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("Concurrent load", func(t *testing.T) {
+		requests = 0
+		key, err := keyFromConfig(confKey)
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, err := key.Signer(context.Background())
+				assert.NoError(t, err)
+			}()
+			go func() {
+				defer wg.Done()
+				_, err := key.ReadyToSign(context.Background())
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		// the key was requested once for all the signers
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("Fail status", func(t *testing.T) {
+		conf := confKey
+		conf.AuthToken = "wrong"
+		key, err := keyFromConfig(conf)
+		assert.NoError(t, err)
+
+		_, err = key.PrivateKey()
+		assert.EqualError(t, err, "could not load the key from "+server.URL+"/key, the server returned status 403")
+	})
+
+	t.Run("Fail size", func(t *testing.T) {
+		conf := confKey
+		conf.Location = server.URL + "/large"
+		key, err := keyFromConfig(conf)
+		assert.NoError(t, err)
+
+		_, err = key.PrivateKey()
+		assert.ErrorContains(t, err, "exceeds the maximum size")
+	})
+
+	t.Run("Fail HTTP", func(t *testing.T) {
+		conf := confKey
+		conf.Location = "http://keys.example.com/key"
+		_, err := keyFromConfig(conf)
+		assert.EqualError(t, err, "key URL http://keys.example.com/key must use https")
+	})
+}
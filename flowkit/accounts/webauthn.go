@@ -0,0 +1,175 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// WebAuthnAuthenticator requests assertions from a WebAuthn authenticator, such as a passkey.
+//
+// Flowkit can't reach the platform authenticator itself, so desktop tooling able to invoke it
+// must set an authenticator using SetWebAuthnAuthenticator before WebAuthn keys can sign.
+//
+// Flow verifies an ECDSA signature over the digest of the transaction message, while a standard WebAuthn
+// assertion signs authenticatorData || SHA-256(clientDataJSON), which never verifies as a Flow signature.
+// The authenticator must therefore return the signature of the credential over the digest itself.
+type WebAuthnAuthenticator interface {
+	// PublicKey returns the public key of the credential
+	PublicKey(ctx context.Context, credentialID string) (crypto.PublicKey, error)
+	// Assert requests a signature over the digest from the credential and returns it DER encoded,
+	// as WebAuthn ES256 signatures are, or in the raw r || s format
+	Assert(ctx context.Context, credentialID string, digest []byte) ([]byte, error)
+}
+
+var webAuthnAuthenticator WebAuthnAuthenticator
+
+// SetWebAuthnAuthenticator sets the authenticator used by WebAuthn keys to sign.
+func SetWebAuthnAuthenticator(authenticator WebAuthnAuthenticator) {
+	webAuthnAuthenticator = authenticator
+}
+
+var _ Key = &WebAuthnKey{}
+
+// WebAuthnKey is a key held by a WebAuthn authenticator and identified by the credential ID.
+//
+// Signing triggers an assertion on the authenticator, which usually requires user interaction,
+// so the key can only be used in interactive environments.
+type WebAuthnKey struct {
+	*baseKey
+	credentialID string
+}
+
+func webAuthnKeyFromConfig(key config.AccountKey) (*WebAuthnKey, error) {
+	if key.CredentialID == "" {
+		return nil, fmt.Errorf("missing credential ID for the webauthn key")
+	}
+
+	return &WebAuthnKey{
+		baseKey:      baseKeyFromConfig(key),
+		credentialID: key.CredentialID,
+	}, nil
+}
+
+func (w *WebAuthnKey) Signer(ctx context.Context) (crypto.Signer, error) {
+	if err := w.checkAlgorithms(); err != nil {
+		return nil, err
+	}
+
+	if signerProvider != nil {
+		return signerProvider.Signer(ctx, w)
+	}
+
+	if webAuthnAuthenticator == nil {
+		return nil, fmt.Errorf("webauthn keys can only be used in interactive environments with an authenticator available")
+	}
+
+	publicKey, err := webAuthnAuthenticator.PublicKey(ctx, w.credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the public key of the webauthn credential: %w", err)
+	}
+
+	return &webAuthnSigner{
+		ctx:           ctx,
+		key:           w,
+		authenticator: webAuthnAuthenticator,
+		publicKey:     publicKey,
+	}, nil
+}
+
+// ReadyToSign checks an authenticator is available.
+func (w *WebAuthnKey) ReadyToSign(_ context.Context) (bool, error) {
+	if signerProvider != nil || webAuthnAuthenticator != nil {
+		return true, nil
+	}
+	return false, fmt.Errorf("webauthn keys can only be used in interactive environments with an authenticator available")
+}
+
+func (w *WebAuthnKey) PrivateKey() (*crypto.PrivateKey, error) {
+	return nil, fmt.Errorf("private key not accessible")
+}
+
+func (w *WebAuthnKey) ToFlowAccountKey() (*flow.AccountKey, error) {
+	return flowAccountKey(w)
+}
+
+func (w *WebAuthnKey) AddKeyArgs() ([]cadence.Value, error) {
+	return addKeyArgs(w)
+}
+
+func (w *WebAuthnKey) ToConfig() config.AccountKey {
+	return config.AccountKey{
+		Type:         w.keyType,
+		Index:        w.index,
+		Weight:       w.weight,
+		WeightSet:    w.weightSet,
+		SigAlgo:      w.sigAlgo,
+		HashAlgo:     w.hashAlgo,
+		CredentialID: w.credentialID,
+	}
+}
+
+func (w *WebAuthnKey) String() string {
+	return fmt.Sprintf("WebAuthnKey{%s, credentialID:%s}", w.fields(), w.credentialID)
+}
+
+var _ crypto.Signer = &webAuthnSigner{}
+
+type webAuthnSigner struct {
+	ctx           context.Context
+	key           *WebAuthnKey
+	authenticator WebAuthnAuthenticator
+	publicKey     crypto.PublicKey
+}
+
+// Sign hashes the message with the key hash algorithm and requests an assertion over the digest,
+// the signature is converted to the raw r || s format used by Flow.
+func (s *webAuthnSigner) Sign(message []byte) ([]byte, error) {
+	hasher, err := crypto.NewHasher(s.key.HashAlgo())
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.authenticator.Assert(s.ctx, s.key.credentialID, hasher.ComputeHash(message))
+	if err != nil {
+		return nil, fmt.Errorf("webauthn assertion failed: %w", err)
+	}
+
+	signature, err = decodeECDSASignature(signature, "")
+	if err != nil {
+		return nil, fmt.Errorf("webauthn authenticator returned invalid signature: %w", err)
+	}
+
+	if s.key.SigAlgo() == crypto.ECDSA_secp256k1 {
+		signature = normalizeLowS(signature)
+	}
+
+	return signature, nil
+}
+
+func (s *webAuthnSigner) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
@@ -0,0 +1,108 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"context"
+	goecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-cli/flowkit/config"
+)
+
+// testAuthenticator signs the digest with the P-256 key returning DER signatures like ES256 authenticators.
+type testAuthenticator struct {
+	privateKey *goecdsa.PrivateKey
+	publicKey  crypto.PublicKey
+	digests    [][]byte
+}
+
+func newTestAuthenticator(t *testing.T, keyHex string) *testAuthenticator {
+	pkey, err := crypto.DecodePrivateKeyHex(crypto.ECDSA_P256, keyHex)
+	assert.NoError(t, err)
+
+	d, _ := new(big.Int).SetString(keyHex, 16)
+	privateKey := &goecdsa.PrivateKey{PublicKey: goecdsa.PublicKey{Curve: elliptic.P256()}, D: d}
+	privateKey.PublicKey.X, privateKey.PublicKey.Y = elliptic.P256().ScalarBaseMult(d.Bytes())
+
+	return &testAuthenticator{privateKey: privateKey, publicKey: pkey.PublicKey()}
+}
+
+func (a *testAuthenticator) PublicKey(_ context.Context, _ string) (crypto.PublicKey, error) {
+	return a.publicKey, nil
+}
+
+func (a *testAuthenticator) Assert(_ context.Context, _ string, digest []byte) ([]byte, error) {
+	a.digests = append(a.digests, digest)
+	return goecdsa.SignASN1(rand.Reader, a.privateKey, digest)
+}
+
+func Test_WebAuthnKey(t *testing.T) {
+	confKey := config.AccountKey{
+		Type:         config.KeyTypeWebAuthn,
+		SigAlgo:      crypto.ECDSA_P256,
+		HashAlgo:     crypto.SHA2_256,
+		CredentialID: "credential",
+	}
+
+	key, err := keyFromConfig(confKey)
+	assert.NoError(t, err)
+	assert.Equal(t, confKey, key.ToConfig())
+
+	_, err = key.PrivateKey()
+	assert.EqualError(t, err, "private key not accessible")
+
+	t.Run("Headless", func(t *testing.T) {
+		_, err := key.Signer(context.Background())
+		assert.EqualError(t, err, "webauthn keys can only be used in interactive environments with an authenticator available")
+	})
+
+	t.Run("Sign", func(t *testing.T) {
+		authenticator := newTestAuthenticator(t, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+		SetWebAuthnAuthenticator(authenticator)
+		defer SetWebAuthnAuthenticator(nil)
+
+		signer, err := key.Signer(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, authenticator.publicKey, signer.PublicKey())
+
+		signature, err := signer.Sign([]byte("message"))
+		assert.NoError(t, err)
+		assert.Len(t, signature, 64)
+
+		hasher, err := crypto.NewHasher(crypto.SHA2_256)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{hasher.ComputeHash([]byte("message"))}, authenticator.digests)
+
+		// the DER assertion is converted to a signature Flow verifies with the public key
+		valid, err := authenticator.publicKey.Verify(signature, []byte("message"), hasher)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	confKey.CredentialID = ""
+	_, err = keyFromConfig(confKey)
+	assert.EqualError(t, err, "missing credential ID for the webauthn key")
+}
@@ -20,6 +20,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -31,19 +32,47 @@ const (
 	DefaultSigAlgo  = crypto.ECDSA_P256
 )
 
+var (
+	defaultSigAlgo  crypto.SignatureAlgorithm = DefaultSigAlgo
+	defaultHashAlgo crypto.HashAlgorithm      = DefaultHashAlgo
+)
+
+// SetDefaultAlgorithms sets the project default algorithms used by keys that don't specify them.
+//
+// Advanced keys in the configuration file without algorithms are loaded with the defaults, so the defaults
+// must be set before loading the configuration. The defaults are not saved in the configuration, so keys
+// using algorithms other than DefaultSigAlgo and DefaultHashAlgo are always saved with explicit algorithms,
+// while simple format keys always use DefaultSigAlgo and DefaultHashAlgo.
+func SetDefaultAlgorithms(sigAlgo crypto.SignatureAlgorithm, hashAlgo crypto.HashAlgorithm) {
+	defaultSigAlgo = sigAlgo
+	defaultHashAlgo = hashAlgo
+}
+
+// DefaultAlgorithms returns the project default signature and hash algorithms.
+func DefaultAlgorithms() (crypto.SignatureAlgorithm, crypto.HashAlgorithm) {
+	return defaultSigAlgo, defaultHashAlgo
+}
+
 // Account defines the configuration for a Flow account.
 type Account struct {
 	Name    string
 	Address flow.Address
 	Key     AccountKey
+	Labels  map[string]string
+	// RoleKeyIndices maps transaction roles to the key index used for the role instead of the key index.
+	RoleKeyIndices map[string]int
 }
 
 type Accounts []Account
 
 // AccountKey represents account key and all their possible configuration formats.
 type AccountKey struct {
-	Type           KeyType
-	Index          int
+	Type   KeyType
+	Index  int
+	Weight int
+	// WeightSet marks the weight as configured, so a configured weight of zero is kept apart from
+	// an unset weight, which is zero as well and means the key has the full weight.
+	WeightSet      bool
 	SigAlgo        crypto.SignatureAlgorithm
 	HashAlgo       crypto.HashAlgorithm
 	ResourceID     string
@@ -52,6 +81,76 @@ type AccountKey struct {
 	PrivateKey     crypto.PrivateKey
 	Location       string
 	Env            string
+	Encrypted      *EncryptedSecret
+	GcloudAccount  string
+	// SLIP-0010 curve of the BIP44 key overriding the curve inferred from the signature algorithm
+	Curve Curve
+	// remote signer service
+	Endpoint           string
+	AuthToken          string
+	InsecureSkipVerify bool
+	SignatureFormat    SignatureFormat
+	// OS credential store entry
+	KeychainService string
+	KeychainAccount string
+	// webauthn credential
+	CredentialID string
+	// request headers used to load the key from a URL
+	Headers map[string]string
+	// command printing the key, such as a secret manager CLI
+	Command []string
+	// keys derived from the mnemonic of the BIP44 key with other algorithms or derivation paths
+	Derivations []KeyDerivation
+}
+
+// KeyDerivation is a key derived from the mnemonic of a BIP44 key, such as a key of a different signature
+// algorithm, which is loaded as a separate account with the name.
+type KeyDerivation struct {
+	Name string
+	// Address of the account using the derived key, defaults to the address of the account with the BIP44 key.
+	Address        flow.Address
+	Index          int
+	SigAlgo        crypto.SignatureAlgorithm
+	HashAlgo       crypto.HashAlgorithm
+	DerivationPath string
+}
+
+// EncryptedSecret is a private key or mnemonic encrypted with a key derived from a passphrase.
+//
+// The encryption key is derived using scrypt with the stored parameters and the secret is encrypted with AES-GCM.
+type EncryptedSecret struct {
+	Ciphertext []byte
+	Nonce      []byte
+	Salt       []byte
+	N          int
+	R          int
+	P          int
+}
+
+// privateKeyHexLength is the length of the hex encoded private key for each supported signature algorithm.
+var privateKeyHexLength = map[crypto.SignatureAlgorithm]int{
+	crypto.ECDSA_P256:      64,
+	crypto.ECDSA_secp256k1: 64,
+}
+
+// DecodePrivateKeyHex decodes the hex encoded private key for the signature algorithm.
+//
+// Some tools export private keys without leading zero bytes, resulting in a hex value shorter
+// than expected. Such keys are left-padded with zeros before decoding and a warning is reported.
+func DecodePrivateKeyHex(sigAlgo crypto.SignatureAlgorithm, key string) (crypto.PrivateKey, error) {
+	key = strings.TrimPrefix(strings.TrimSpace(key), "0x")
+
+	length, ok := privateKeyHexLength[sigAlgo]
+	if ok && len(key) > 0 && len(key) < length {
+		Warn(fmt.Sprintf(
+			"private key has %d hex characters instead of %d, padding it with leading zeros",
+			len(key),
+			length,
+		))
+		key = strings.Repeat("0", length-len(key)) + key
+	}
+
+	return crypto.DecodePrivateKeyHex(sigAlgo, key)
 }
 
 func NewDefaultAccountKey(pkey crypto.PrivateKey) AccountKey {
@@ -63,11 +162,27 @@ func NewDefaultAccountKey(pkey crypto.PrivateKey) AccountKey {
 	}
 }
 
+// Algorithms returns the key algorithms, using the project defaults for the algorithms not set.
+func (a *AccountKey) Algorithms() (crypto.SignatureAlgorithm, crypto.HashAlgorithm) {
+	sigAlgo, hashAlgo := a.SigAlgo, a.HashAlgo
+	if sigAlgo == crypto.UnknownSignatureAlgorithm {
+		sigAlgo = defaultSigAlgo
+	}
+	if hashAlgo == crypto.UnknownHashAlgorithm {
+		hashAlgo = defaultHashAlgo
+	}
+	return sigAlgo, hashAlgo
+}
+
+// IsDefault checks the key can be saved in the simple format, which always uses DefaultSigAlgo and DefaultHashAlgo.
 func (a *AccountKey) IsDefault() bool {
+	sigAlgo, hashAlgo := a.Algorithms()
 	return a.Index == 0 &&
+		a.Weight == 0 &&
 		a.Type == KeyTypeHex &&
-		a.SigAlgo == DefaultSigAlgo &&
-		a.HashAlgo == DefaultHashAlgo
+		a.Encrypted == nil &&
+		sigAlgo == DefaultSigAlgo &&
+		hashAlgo == DefaultHashAlgo
 }
 
 // ByName get account by name or error if not found.
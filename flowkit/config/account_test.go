@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -117,3 +118,30 @@ func TestAccounts_Remove(t *testing.T) {
 	accounts.Remove("account4")
 	assert.Equal(t, len(accounts), 2)
 }
+
+func TestDecodePrivateKeyHex(t *testing.T) {
+	const short = "5d3fc6e4b0a7bbc4dc13b05cbc75de3c26e6c24fe1e75d4ee4c0cd21d3f4a4"
+	const full = "00" + short
+
+	t.Run("Full length key", func(t *testing.T) {
+		key, err := DecodePrivateKeyHex(crypto.ECDSA_P256, "0x"+full)
+		assert.NoError(t, err)
+		assert.Equal(t, "0x"+full, key.String())
+	})
+
+	t.Run("Short key is padded", func(t *testing.T) {
+		for _, sigAlgo := range []crypto.SignatureAlgorithm{crypto.ECDSA_P256, crypto.ECDSA_secp256k1} {
+			key, err := DecodePrivateKeyHex(sigAlgo, short)
+			assert.NoError(t, err)
+			assert.Equal(t, "0x"+full, key.String())
+		}
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		_, err := DecodePrivateKeyHex(crypto.ECDSA_P256, "")
+		assert.Error(t, err)
+
+		_, err = DecodePrivateKeyHex(crypto.ECDSA_P256, "zz")
+		assert.Error(t, err)
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 )
 
 // Config contains all the configuration for CLI and implements getters and setters for properties.
@@ -43,12 +44,122 @@ type Config struct {
 type KeyType string
 
 const (
-	KeyTypeHex       KeyType = "hex"
-	KeyTypeGoogleKMS KeyType = "google-kms"
-	KeyTypeBip44     KeyType = "bip44"
-	KeyTypeFile      KeyType = "file"
+	KeyTypeHex        KeyType = "hex"
+	KeyTypeGoogleKMS  KeyType = "google-kms"
+	KeyTypeBip44      KeyType = "bip44"
+	KeyTypeFile       KeyType = "file"
+	KeyTypeKMS        KeyType = "kms" // provider is detected from the resource ID
+	KeyTypeAWSKMS     KeyType = "aws-kms"
+	KeyTypeAzureKMS   KeyType = "azure-kms"
+	KeyTypeRemoteHTTP KeyType = "remote-http"
+	KeyTypeKeychain   KeyType = "keychain"
+	KeyTypeWebAuthn   KeyType = "webauthn"
+	KeyTypeURL        KeyType = "url"
+	KeyTypeCommand    KeyType = "command"
+	KeyTypeKeystore   KeyType = "keystore"
 )
 
+// keyTypes are the key types that can be used in the configuration.
+//
+// AWS and Azure KMS types are only detected from the resource ID of the kms key type.
+var keyTypes = []KeyType{
+	KeyTypeHex,
+	KeyTypeFile,
+	KeyTypeBip44,
+	KeyTypeGoogleKMS,
+	KeyTypeKMS,
+	KeyTypeRemoteHTTP,
+	KeyTypeKeychain,
+	KeyTypeWebAuthn,
+	KeyTypeURL,
+	KeyTypeCommand,
+	KeyTypeKeystore,
+}
+
+// IsValid returns whether the key type can be used in the configuration.
+func (k KeyType) IsValid() bool {
+	for _, t := range keyTypes {
+		if k == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SignatureFormat is the encoding of the ECDSA signatures returned by a remote signer.
+type SignatureFormat string
+
+const (
+	SignatureFormatAuto SignatureFormat = ""    // detected from the returned signature
+	SignatureFormatRaw  SignatureFormat = "raw" // r || s, each padded to the curve size
+	SignatureFormatDER  SignatureFormat = "der" // ASN.1 DER sequence of r and s
+)
+
+// IsValid returns whether the signature format can be used in the configuration.
+func (f SignatureFormat) IsValid() bool {
+	return f == SignatureFormatAuto || f == SignatureFormatRaw || f == SignatureFormatDER
+}
+
+// Curve is the SLIP-0010 curve name used to derive a BIP44 key from the mnemonic seed.
+type Curve string
+
+const (
+	CurveInferred  Curve = "" // inferred from the signature algorithm
+	CurveNIST256P1 Curve = "nist256p1"
+	CurveSecp256k1 Curve = "secp256k1"
+)
+
+// ParseKeyType parses the key type and suggests the closest valid key type if it's not valid.
+func ParseKeyType(s string) (KeyType, error) {
+	keyType := KeyType(strings.ToLower(strings.TrimSpace(s)))
+	if keyType.IsValid() {
+		return keyType, nil
+	}
+
+	suggestion := ""
+	minDistance := 3 // only suggest close matches
+	for _, t := range keyTypes {
+		if d := editDistance(string(keyType), string(t)); d < minDistance {
+			suggestion = string(t)
+			minDistance = d
+		}
+	}
+
+	if suggestion != "" {
+		return "", fmt.Errorf("invalid key type %q, did you mean %q?", s, suggestion)
+	}
+	return "", fmt.Errorf("invalid key type %q", s)
+}
+
+// editDistance returns the Levenshtein distance between the strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost // substitution
+			if prev[j]+1 < cur[j] {   // deletion
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] { // insertion
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
 // Validate the configuration values.
 func (c *Config) Validate() error {
 	for _, con := range c.Contracts {
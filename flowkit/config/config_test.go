@@ -340,3 +340,25 @@ func Test_DefaultPath(t *testing.T) {
 	def := config.DefaultPaths()
 	assert.True(t, config.IsDefaultPath(def))
 }
+
+func Test_ParseKeyType(t *testing.T) {
+	keyType, err := config.ParseKeyType("google-kms")
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeGoogleKMS, keyType)
+	assert.True(t, keyType.IsValid())
+
+	keyType, err = config.ParseKeyType(" HEX ")
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeHex, keyType)
+
+	_, err = config.ParseKeyType("googel-kms")
+	assert.EqualError(t, err, `invalid key type "googel-kms", did you mean "google-kms"?`)
+
+	_, err = config.ParseKeyType("bip32")
+	assert.EqualError(t, err, `invalid key type "bip32", did you mean "bip44"?`)
+
+	_, err = config.ParseKeyType("ledger")
+	assert.EqualError(t, err, `invalid key type "ledger"`)
+
+	assert.False(t, config.KeyTypeAWSKMS.IsValid())
+}
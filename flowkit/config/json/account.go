@@ -19,6 +19,7 @@
 package json
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -34,11 +35,6 @@ import (
 
 type jsonAccounts map[string]account
 
-const (
-	defaultHashAlgo = crypto.SHA3_256
-	defaultSigAlgo  = crypto.ECDSA_P256
-)
-
 // transformAddress returns address based on address and chain id.
 func transformAddress(address string) (flow.Address, error) {
 	// only allow service for emulator
@@ -55,10 +51,11 @@ func transformAddress(address string) (flow.Address, error) {
 
 // transformSimpleToConfig transforms simple internal account to config account.
 func transformSimpleToConfig(accountName string, a simpleAccount) (*config.Account, error) {
+	// the simple format doesn't depend on the project defaults, so the file means the same key in every project
 	key := config.AccountKey{
 		Type:     config.KeyTypeHex,
-		SigAlgo:  defaultSigAlgo,
-		HashAlgo: defaultHashAlgo,
+		SigAlgo:  config.DefaultSigAlgo,
+		HashAlgo: config.DefaultHashAlgo,
 	}
 
 	replaced, original, err := tryReplaceEnv(a.Key)
@@ -70,10 +67,7 @@ func transformSimpleToConfig(accountName string, a simpleAccount) (*config.Accou
 		a.Key = replaced
 	}
 
-	pkey, err := crypto.DecodePrivateKeyHex(
-		config.DefaultSigAlgo,
-		strings.TrimPrefix(a.Key, "0x"),
-	)
+	pkey, err := config.DecodePrivateKeyHex(config.DefaultSigAlgo, a.Key)
 	if err != nil {
 		return nil, fmt.Errorf("invalid private key for account: %s", accountName)
 	}
@@ -93,9 +87,11 @@ func transformSimpleToConfig(accountName string, a simpleAccount) (*config.Accou
 	}
 
 	return &config.Account{
-		Name:    accountName,
-		Address: address,
-		Key:     key,
+		Name:           accountName,
+		Address:        address,
+		Key:            key,
+		Labels:         a.Labels,
+		RoleKeyIndices: a.RoleKeyIndices,
 	}, nil
 }
 
@@ -132,33 +128,45 @@ func tryReplaceEnv(value string) (replaced string, original string, err error) {
 
 // transformAdvancedToConfig transforms advanced internal account to config account.
 func transformAdvancedToConfig(accountName string, a advancedAccount) (*config.Account, error) {
-	sigAlgo := config.DefaultSigAlgo // default to ecdsa as default
+	// unspecified algorithms use the project defaults
+	sigAlgo, hashAlgo := config.DefaultAlgorithms()
 	if a.Key.SigAlgo != "" {
 		sigAlgo = crypto.StringToSignatureAlgorithm(a.Key.SigAlgo)
+		if sigAlgo == crypto.UnknownSignatureAlgorithm {
+			return nil, fmt.Errorf("invalid signature algorithm for account %s", accountName)
+		}
 	}
 
-	if sigAlgo == crypto.UnknownSignatureAlgorithm {
-		return nil, fmt.Errorf("invalid signature algorithm for account %s", accountName)
-	}
-
-	hashAlgo := config.DefaultHashAlgo // default to sha3 as default
 	if a.Key.HashAlgo != "" {
 		hashAlgo = crypto.StringToHashAlgorithm(a.Key.HashAlgo)
+		if hashAlgo == crypto.UnknownHashAlgorithm {
+			return nil, fmt.Errorf("invalid hash algorithm for account %s", accountName)
+		}
 	}
 
-	if hashAlgo == crypto.UnknownHashAlgorithm {
-		return nil, fmt.Errorf("invalid hash algorithm for account %s", accountName)
+	weight := 0
+	if a.Key.Weight != nil {
+		weight = *a.Key.Weight
+		if weight < 0 || weight > flow.AccountKeyWeightThreshold {
+			return nil, fmt.Errorf("invalid key weight for account %s, must be between 0 and %d", accountName, flow.AccountKeyWeightThreshold)
+		}
 	}
 
-	validTypes := []config.KeyType{config.KeyTypeHex, config.KeyTypeFile, config.KeyTypeBip44, config.KeyTypeGoogleKMS}
-	if !slices.Contains(validTypes, a.Key.Type) {
-		return nil, fmt.Errorf("invalid key type for account %s", accountName)
+	keyType, err := config.ParseKeyType(string(a.Key.Type))
+	if err != nil {
+		return nil, fmt.Errorf("account %s: %w", accountName, err)
 	}
+	a.Key.Type = keyType
 
-	// check that only one is provided because the values are mutually exclusive
+	if err := checkKeyFields(accountName, a.Key); err != nil {
+		return nil, err
+	}
+
+	// check that only one is provided because the values are mutually exclusive,
+	// except for keystore keys referencing the key by ID in the keystore at the location
 	set := false
 	for _, v := range []string{a.Key.ResourceID, a.Key.PrivateKey, a.Key.Location} {
-		if v == "" {
+		if v == "" || keyType == config.KeyTypeKeystore {
 			continue
 		}
 		if set {
@@ -167,20 +175,35 @@ func transformAdvancedToConfig(accountName string, a advancedAccount) (*config.A
 		set = true
 	}
 
+	if a.Key.Encrypted != nil && (a.Key.PrivateKey != "" || a.Key.Mnemonic != "") {
+		return nil, fmt.Errorf("can not provide both encrypted and plain secret on account %s", accountName)
+	}
+
 	address, err := transformAddress(a.Address)
 	if err != nil {
 		return nil, err
 	}
 
 	key := config.AccountKey{
-		Type:     a.Key.Type,
-		Index:    a.Key.Index,
-		SigAlgo:  sigAlgo,
-		HashAlgo: hashAlgo,
+		Type:      a.Key.Type,
+		Index:     a.Key.Index,
+		Weight:    weight,
+		WeightSet: a.Key.Weight != nil, // an explicit zero weight is kept apart from the unset full weight
+		SigAlgo:   sigAlgo,
+		HashAlgo:  hashAlgo,
 	}
 
 	switch a.Key.Type {
 	case config.KeyTypeHex:
+		if a.Key.PrivateKey == "" && a.Key.Encrypted != nil {
+			encrypted, err := a.Key.Encrypted.transformToConfig()
+			if err != nil {
+				return nil, fmt.Errorf("invalid encrypted private key on account %s: %w", accountName, err)
+			}
+			key.Encrypted = encrypted
+			break
+		}
+
 		if a.Key.PrivateKey == "" {
 			return nil, fmt.Errorf("missing private key value for hex key type on account %s", accountName)
 		}
@@ -194,45 +217,157 @@ func transformAdvancedToConfig(accountName string, a advancedAccount) (*config.A
 			a.Key.PrivateKey = replaced
 		}
 
-		pKey, err := crypto.DecodePrivateKeyHex(
-			sigAlgo,
-			strings.TrimPrefix(a.Key.PrivateKey, "0x"),
-		)
+		pKey, err := config.DecodePrivateKeyHex(sigAlgo, a.Key.PrivateKey)
 		if err != nil {
 			return nil, err
 		}
 
 		key.PrivateKey = pKey
 	case config.KeyTypeBip44:
-		if a.Key.Mnemonic == "" {
-			return nil, fmt.Errorf("missing mnemonic value for bip44 key type on account %s", accountName)
+		if a.Key.Mnemonic == "" && a.Key.Encrypted != nil {
+			encrypted, err := a.Key.Encrypted.transformToConfig()
+			if err != nil {
+				return nil, fmt.Errorf("invalid encrypted mnemonic on account %s: %w", accountName, err)
+			}
+			key.Encrypted = encrypted
 		}
+		// without a mnemonic it's entered when signing, so it's never stored in the configuration
 		key.Mnemonic = a.Key.Mnemonic
 		key.DerivationPath = a.Key.DerivationPath
 		if key.DerivationPath == "" {
 			key.DerivationPath = "m/44'/539'/0'/0/0"
 		}
+		key.Curve = config.Curve(strings.ToLower(a.Key.Curve))
+
+		for _, d := range a.Key.Derivations {
+			derivation, err := d.transformToConfig(accountName)
+			if err != nil {
+				return nil, err
+			}
+			key.Derivations = append(key.Derivations, *derivation)
+		}
 
-	case config.KeyTypeGoogleKMS:
+	case config.KeyTypeGoogleKMS, config.KeyTypeKMS:
 		if a.Key.ResourceID == "" {
 			return nil, fmt.Errorf("missing resource ID value for key on account %s", accountName)
 		}
 		key.ResourceID = a.Key.ResourceID
+		key.GcloudAccount = a.Key.GcloudAccount
 
 	case config.KeyTypeFile:
 		if a.Key.Location == "" {
 			return nil, fmt.Errorf("missing location to a file containing the private key value for the account %s", accountName)
 		}
 		key.Location = a.Key.Location
+
+	case config.KeyTypeRemoteHTTP:
+		if a.Key.Endpoint == "" || a.Key.ResourceID == "" {
+			return nil, fmt.Errorf("missing endpoint or resource ID value for remote signer key on account %s", accountName)
+		}
+		key.Endpoint = a.Key.Endpoint
+		key.ResourceID = a.Key.ResourceID
+		key.AuthToken = a.Key.AuthToken
+		key.InsecureSkipVerify = a.Key.InsecureSkipVerify
+		key.SignatureFormat = config.SignatureFormat(a.Key.SignatureFormat)
+		if !key.SignatureFormat.IsValid() {
+			return nil, fmt.Errorf("invalid signature format %s for remote signer key on account %s, must be raw or der", a.Key.SignatureFormat, accountName)
+		}
+
+	case config.KeyTypeKeychain:
+		if a.Key.KeychainService == "" || a.Key.KeychainAccount == "" {
+			return nil, fmt.Errorf("missing keychain service or account value for keychain key on account %s", accountName)
+		}
+		key.KeychainService = a.Key.KeychainService
+		key.KeychainAccount = a.Key.KeychainAccount
+
+	case config.KeyTypeWebAuthn:
+		if a.Key.CredentialID == "" {
+			return nil, fmt.Errorf("missing credential ID value for webauthn key on account %s", accountName)
+		}
+		key.CredentialID = a.Key.CredentialID
+
+	case config.KeyTypeURL:
+		if a.Key.Location == "" {
+			return nil, fmt.Errorf("missing location to a URL returning the private key value for the account %s", accountName)
+		}
+		key.Location = a.Key.Location
+		key.AuthToken = a.Key.AuthToken
+		key.Headers = a.Key.Headers
+
+	case config.KeyTypeCommand:
+		if len(a.Key.Command) == 0 {
+			return nil, fmt.Errorf("missing command printing the private key value for the account %s", accountName)
+		}
+		key.Command = a.Key.Command
+
+	case config.KeyTypeKeystore:
+		if a.Key.Location == "" {
+			return nil, fmt.Errorf("missing keystore location for the account %s", accountName)
+		}
+		if a.Key.ResourceID == "" {
+			return nil, fmt.Errorf("missing key ID in the keystore for the account %s", accountName)
+		}
+		key.Location = a.Key.Location
+		key.ResourceID = a.Key.ResourceID
 	}
 
 	return &config.Account{
-		Name:    accountName,
-		Address: address,
-		Key:     key,
+		Name:           accountName,
+		Address:        address,
+		Key:            key,
+		Labels:         a.Labels,
+		RoleKeyIndices: a.RoleKeyIndices,
 	}, nil
 }
 
+// checkKeyFields reports key properties that are ignored because they don't apply to the key type,
+// such as a mnemonic on a hex key, which otherwise silently results in using a different key.
+func checkKeyFields(accountName string, key advanceKey) error {
+	kmsTypes := []config.KeyType{config.KeyTypeGoogleKMS, config.KeyTypeKMS}
+	fields := []struct {
+		name  string
+		set   bool
+		types []config.KeyType
+	}{
+		{"privateKey", key.PrivateKey != "", []config.KeyType{config.KeyTypeHex}},
+		{"mnemonic", key.Mnemonic != "", []config.KeyType{config.KeyTypeBip44}},
+		{"derivationPath", key.DerivationPath != "", []config.KeyType{config.KeyTypeBip44}},
+		{"curve", key.Curve != "", []config.KeyType{config.KeyTypeBip44}},
+		{"resourceID", key.ResourceID != "", append(kmsTypes, config.KeyTypeRemoteHTTP, config.KeyTypeKeystore)},
+		{"gcloudAccount", key.GcloudAccount != "", kmsTypes},
+		{"location", key.Location != "", []config.KeyType{config.KeyTypeFile, config.KeyTypeURL, config.KeyTypeKeystore}},
+		{"encrypted", key.Encrypted != nil, []config.KeyType{config.KeyTypeHex, config.KeyTypeBip44}},
+		{"endpoint", key.Endpoint != "", []config.KeyType{config.KeyTypeRemoteHTTP}},
+		{"authToken", key.AuthToken != "", []config.KeyType{config.KeyTypeRemoteHTTP, config.KeyTypeURL}},
+		{"insecureSkipVerify", key.InsecureSkipVerify, []config.KeyType{config.KeyTypeRemoteHTTP}},
+		{"signatureFormat", key.SignatureFormat != "", []config.KeyType{config.KeyTypeRemoteHTTP}},
+		{"keychainService", key.KeychainService != "", []config.KeyType{config.KeyTypeKeychain}},
+		{"keychainAccount", key.KeychainAccount != "", []config.KeyType{config.KeyTypeKeychain}},
+		{"credentialID", key.CredentialID != "", []config.KeyType{config.KeyTypeWebAuthn}},
+		{"headers", len(key.Headers) > 0, []config.KeyType{config.KeyTypeURL}},
+		{"command", len(key.Command) > 0, []config.KeyType{config.KeyTypeCommand}},
+		{"derivations", len(key.Derivations) > 0, []config.KeyType{config.KeyTypeBip44}},
+	}
+
+	var ignored []string
+	for _, f := range fields {
+		if f.set && !slices.Contains(f.types, key.Type) {
+			ignored = append(ignored, f.name)
+		}
+	}
+
+	if len(ignored) == 0 {
+		return nil
+	}
+
+	return config.WarnStrict(fmt.Sprintf(
+		"account %s has %s set, which is not used by %s keys",
+		accountName,
+		strings.Join(ignored, ", "),
+		key.Type,
+	))
+}
+
 // transformToConfig transforms json structures to config structure.
 func (j jsonAccounts) transformToConfig() (config.Accounts, error) {
 	accounts := make(config.Accounts, 0)
@@ -281,8 +416,10 @@ func transformSimpleAccountToJSON(a config.Account) account {
 
 	return account{
 		Simple: simpleAccount{
-			Address: a.Address.String(),
-			Key:     key,
+			Address:        a.Address.String(),
+			Key:            key,
+			Labels:         a.Labels,
+			RoleKeyIndices: a.RoleKeyIndices,
 		},
 	}
 }
@@ -290,8 +427,10 @@ func transformSimpleAccountToJSON(a config.Account) account {
 func transformAdvancedAccountToJSON(a config.Account) account {
 	return account{
 		Advanced: advancedAccount{
-			Address: a.Address.String(),
-			Key:     transformAdvancedKeyToJSON(a.Key),
+			Address:        a.Address.String(),
+			Key:            transformAdvancedKeyToJSON(a.Key),
+			Labels:         a.Labels,
+			RoleKeyIndices: a.RoleKeyIndices,
 		},
 	}
 }
@@ -305,27 +444,71 @@ func transformAdvancedKeyToJSON(key config.AccountKey) advanceKey {
 		advancedKey.Index = key.Index
 	}
 
-	if key.SigAlgo != config.DefaultSigAlgo { // only set if non-default
-		advancedKey.SigAlgo = key.SigAlgo.String()
+	if key.Weight != 0 || key.WeightSet { // only set if non-default
+		weight := key.Weight
+		advancedKey.Weight = &weight
+	}
+
+	// the project defaults are not saved, so only the file format defaults are omitted
+	sigAlgo, hashAlgo := key.Algorithms()
+	if sigAlgo != config.DefaultSigAlgo { // only set if non-default
+		advancedKey.SigAlgo = sigAlgo.String()
 	}
 
-	if key.HashAlgo != config.DefaultHashAlgo { // only set if non-default
-		advancedKey.HashAlgo = key.HashAlgo.String()
+	if hashAlgo != config.DefaultHashAlgo { // only set if non-default
+		advancedKey.HashAlgo = hashAlgo.String()
+	}
+
+	if key.Encrypted != nil { // never save the decrypted secret
+		advancedKey.Encrypted = transformEncryptedToJSON(key.Encrypted)
 	}
 
 	switch key.Type {
 	case config.KeyTypeHex:
+		if key.Encrypted != nil {
+			break
+		}
 		advancedKey.PrivateKey = strings.TrimPrefix(key.PrivateKey.String(), "0x")
 		if key.Env != "" {
 			advancedKey.PrivateKey = key.Env // if we used env vars then use it when saving
 		}
 	case config.KeyTypeBip44:
-		advancedKey.Mnemonic = key.Mnemonic
+		// the signature algorithm selects the derivation curve, so it's always saved
+		advancedKey.SigAlgo = sigAlgo.String()
 		advancedKey.DerivationPath = key.DerivationPath
-	case config.KeyTypeGoogleKMS:
+		advancedKey.Curve = string(key.Curve)
+		for _, d := range key.Derivations {
+			advancedKey.Derivations = append(advancedKey.Derivations, transformDerivationToJSON(d))
+		}
+		if key.Encrypted != nil {
+			break
+		}
+		advancedKey.Mnemonic = key.Mnemonic
+	case config.KeyTypeGoogleKMS, config.KeyTypeKMS:
 		advancedKey.ResourceID = key.ResourceID
+		advancedKey.GcloudAccount = key.GcloudAccount
 	case config.KeyTypeFile:
 		advancedKey.Location = key.Location
+	case config.KeyTypeRemoteHTTP:
+		advancedKey.Endpoint = key.Endpoint
+		advancedKey.ResourceID = key.ResourceID
+		advancedKey.AuthToken = key.AuthToken
+		advancedKey.InsecureSkipVerify = key.InsecureSkipVerify
+		advancedKey.SignatureFormat = string(key.SignatureFormat)
+	case config.KeyTypeKeychain:
+		advancedKey.KeychainService = key.KeychainService
+		advancedKey.KeychainAccount = key.KeychainAccount
+	case config.KeyTypeWebAuthn:
+		advancedKey.CredentialID = key.CredentialID
+	case config.KeyTypeURL:
+		advancedKey.Location = key.Location
+		advancedKey.AuthToken = key.AuthToken
+		advancedKey.Headers = key.Headers
+	case config.KeyTypeCommand:
+		advancedKey.Command = key.Command
+	case config.KeyTypeKeystore:
+		advancedKey.Location = key.Location
+		advancedKey.ResourceID = key.ResourceID
 	}
 
 	return advancedKey
@@ -337,18 +520,23 @@ type account struct {
 }
 
 type simpleAccount struct {
-	Address string `json:"address"`
-	Key     string `json:"key"`
+	Address        string            `json:"address"`
+	Key            string            `json:"key"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	RoleKeyIndices map[string]int    `json:"roleKeyIndices,omitempty"`
 }
 
 type advancedAccount struct {
-	Address string     `json:"address"`
-	Key     advanceKey `json:"key"`
+	Address        string            `json:"address"`
+	Key            advanceKey        `json:"key"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	RoleKeyIndices map[string]int    `json:"roleKeyIndices,omitempty"`
 }
 
 type advanceKey struct {
 	Type     config.KeyType `json:"type"`
 	Index    int            `json:"index,omitempty"`
+	Weight   *int           `json:"weight,omitempty"`
 	SigAlgo  string         `json:"signatureAlgorithm,omitempty"`
 	HashAlgo string         `json:"hashAlgorithm,omitempty"`
 	// hex key type
@@ -356,14 +544,143 @@ type advanceKey struct {
 	// bip44 key type
 	Mnemonic       string `json:"mnemonic,omitempty"`
 	DerivationPath string `json:"derivationPath,omitempty"`
+	Curve          string `json:"curve,omitempty"`
 	// kms key type
-	ResourceID string `json:"resourceID,omitempty"`
+	ResourceID    string `json:"resourceID,omitempty"`
+	GcloudAccount string `json:"gcloudAccount,omitempty"`
 	// key location
 	Location string `json:"location,omitempty"`
+	// encrypted private key or mnemonic
+	Encrypted *encryptedSecret `json:"encrypted,omitempty"`
+	// remote signer service
+	Endpoint           string `json:"endpoint,omitempty"`
+	AuthToken          string `json:"authToken,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+	SignatureFormat    string `json:"signatureFormat,omitempty"`
+	// OS credential store entry
+	KeychainService string `json:"keychainService,omitempty"`
+	KeychainAccount string `json:"keychainAccount,omitempty"`
+	// webauthn credential
+	CredentialID string `json:"credentialID,omitempty"`
+	// request headers of the url key type
+	Headers map[string]string `json:"headers,omitempty"`
+	// command of the command key type
+	Command []string `json:"command,omitempty"`
+	// keys derived from the mnemonic of the bip44 key type
+	Derivations []keyDerivation `json:"derivations,omitempty"`
 	// old key format
 	Context map[string]string `json:"context,omitempty"`
 }
 
+type keyDerivation struct {
+	Name           string `json:"name"`
+	Address        string `json:"address,omitempty"`
+	Index          int    `json:"index,omitempty"`
+	SigAlgo        string `json:"signatureAlgorithm"`
+	HashAlgo       string `json:"hashAlgorithm,omitempty"`
+	DerivationPath string `json:"derivationPath,omitempty"`
+}
+
+// transformToConfig transforms the key derived from the mnemonic of the account key to the configuration,
+// the signature algorithm is required since deriving the key with the same algorithm only changes the path.
+func (d keyDerivation) transformToConfig(accountName string) (*config.KeyDerivation, error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("missing name of the derived key on account %s", accountName)
+	}
+
+	derivation := &config.KeyDerivation{
+		Name:           d.Name,
+		Index:          d.Index,
+		SigAlgo:        crypto.StringToSignatureAlgorithm(d.SigAlgo),
+		HashAlgo:       crypto.UnknownHashAlgorithm,
+		DerivationPath: d.DerivationPath,
+	}
+
+	if derivation.SigAlgo == crypto.UnknownSignatureAlgorithm {
+		return nil, fmt.Errorf("invalid signature algorithm for the derived key %s on account %s", d.Name, accountName)
+	}
+
+	if d.HashAlgo != "" {
+		derivation.HashAlgo = crypto.StringToHashAlgorithm(d.HashAlgo)
+		if derivation.HashAlgo == crypto.UnknownHashAlgorithm {
+			return nil, fmt.Errorf("invalid hash algorithm for the derived key %s on account %s", d.Name, accountName)
+		}
+	}
+
+	if d.Address != "" {
+		address, err := transformAddress(d.Address)
+		if err != nil {
+			return nil, err
+		}
+		derivation.Address = address
+	}
+
+	return derivation, nil
+}
+
+func transformDerivationToJSON(d config.KeyDerivation) keyDerivation {
+	derivation := keyDerivation{
+		Name:           d.Name,
+		Index:          d.Index,
+		SigAlgo:        d.SigAlgo.String(),
+		DerivationPath: d.DerivationPath,
+	}
+
+	if d.HashAlgo != crypto.UnknownHashAlgorithm {
+		derivation.HashAlgo = d.HashAlgo.String()
+	}
+
+	if d.Address != flow.EmptyAddress {
+		derivation.Address = d.Address.String()
+	}
+
+	return derivation
+}
+
+type encryptedSecret struct {
+	Ciphertext string `json:"ciphertext"`
+	Nonce      string `json:"nonce"`
+	Salt       string `json:"salt"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+}
+
+func (e *encryptedSecret) transformToConfig() (*config.EncryptedSecret, error) {
+	ciphertext, err := hex.DecodeString(e.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	nonce, err := hex.DecodeString(e.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+	salt, err := hex.DecodeString(e.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt")
+	}
+
+	return &config.EncryptedSecret{
+		Ciphertext: ciphertext,
+		Nonce:      nonce,
+		Salt:       salt,
+		N:          e.N,
+		R:          e.R,
+		P:          e.P,
+	}, nil
+}
+
+func transformEncryptedToJSON(e *config.EncryptedSecret) *encryptedSecret {
+	return &encryptedSecret{
+		Ciphertext: hex.EncodeToString(e.Ciphertext),
+		Nonce:      hex.EncodeToString(e.Nonce),
+		Salt:       hex.EncodeToString(e.Salt),
+		N:          e.N,
+		R:          e.R,
+		P:          e.P,
+	}
+}
+
 // support for pre v0.22 formats
 type simpleAccountPre022 struct {
 	Address string `json:"address"`
@@ -450,7 +767,7 @@ func (j *account) UnmarshalJSON(b []byte) error {
 }
 
 func (j account) MarshalJSON() ([]byte, error) {
-	if j.Simple != (simpleAccount{}) {
+	if j.Simple.Key != "" {
 		return json.Marshal(j.Simple)
 	}
 
@@ -155,6 +155,59 @@ func Test_ConfigAccountKeysAdvancedKMS(t *testing.T) {
 	assert.Nil(t, key.PrivateKey)
 }
 
+func Test_ConfigAccountKeysAdvancedEncrypted(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "service",
+			"key": {
+				"type": "hex",
+				"encrypted": {
+					"ciphertext": "0102",
+					"nonce": "03",
+					"salt": "04",
+					"n": 32768,
+					"r": 8,
+					"p": 1
+				}
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+
+	account, err := accounts.ByName("test")
+	assert.NoError(t, err)
+	key := account.Key
+
+	assert.Nil(t, key.PrivateKey)
+	assert.Equal(t, &config.EncryptedSecret{
+		Ciphertext: []byte{1, 2},
+		Nonce:      []byte{3},
+		Salt:       []byte{4},
+		N:          32768,
+		R:          8,
+		P:          1,
+	}, key.Encrypted)
+	assert.False(t, key.IsDefault())
+
+	out, err := json.Marshal(transformAccountsToJSON(accounts))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "hex",
+				"encrypted": {"ciphertext": "0102", "nonce": "03", "salt": "04", "n": 32768, "r": 8, "p": 1}
+			}
+		}
+	}`, string(out))
+}
+
 func Test_ConfigAccountOldFormats(t *testing.T) {
 	b := []byte(`{
 		"old-format-1": {
@@ -520,6 +573,528 @@ func Test_ConfigInvalidKey(t *testing.T) {
 	assert.Equal(t, err.Error(), "invalid private key for account: test")
 }
 
+func Test_ConfigAccountKeyWeight(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "service",
+			"key": {
+				"type": "hex",
+				"weight": 500,
+				"privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			}
+		},
+		"zero": {
+			"address": "service",
+			"key": {
+				"type": "hex",
+				"weight": 0,
+				"privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			}
+		},
+		"unset": {
+			"address": "service",
+			"key": {
+				"type": "hex",
+				"privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			}
+		},
+		"invalid": {
+			"address": "service",
+			"key": {
+				"type": "hex",
+				"weight": 1001,
+				"privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	_, err = jsonAccounts.transformToConfig()
+	assert.EqualError(t, err, "invalid key weight for account invalid, must be between 0 and 1000")
+
+	delete(jsonAccounts, "invalid")
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+
+	acc, err := accounts.ByName("test")
+	assert.NoError(t, err)
+	assert.Equal(t, 500, acc.Key.Weight)
+	assert.False(t, acc.Key.IsDefault())
+
+	assert.Equal(t, 500, *transformAdvancedKeyToJSON(acc.Key).Weight)
+
+	acc, err = accounts.ByName("zero")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, acc.Key.Weight)
+	assert.True(t, acc.Key.WeightSet)
+	assert.Equal(t, 0, *transformAdvancedKeyToJSON(acc.Key).Weight)
+
+	acc, err = accounts.ByName("unset")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, acc.Key.Weight)
+	assert.False(t, acc.Key.WeightSet)
+	assert.Nil(t, transformAdvancedKeyToJSON(acc.Key).Weight)
+}
+
+func Test_ConfigAccountLabels(t *testing.T) {
+	b := []byte(`{
+		"simple": {
+			"address": "f8d6e0586b0a20c7",
+			"key": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47",
+			"labels": {"team": "payments", "env": "staging"}
+		},
+		"advanced": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "hex",
+				"index": 1,
+				"privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			},
+			"labels": {"purpose": "minter"}
+		},
+		"none": {
+			"address": "f8d6e0586b0a20c7",
+			"key": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+
+	simple, err := accounts.ByName("simple")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "env": "staging"}, simple.Labels)
+
+	advanced, err := accounts.ByName("advanced")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"purpose": "minter"}, advanced.Labels)
+
+	none, err := accounts.ByName("none")
+	assert.NoError(t, err)
+	assert.Nil(t, none.Labels)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+}
+
+func Test_ConfigAccountRoleKeyIndices(t *testing.T) {
+	b := []byte(`{
+		"alice": {
+			"address": "f8d6e0586b0a20c7",
+			"key": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47",
+			"roleKeyIndices": {"proposer": 3}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"proposer": 3}, accounts[0].RoleKeyIndices)
+
+	x, _ := json.Marshal(transformAccountsToJSON(accounts))
+	assert.JSONEq(t, string(b), string(x))
+}
+
+func Test_ConfigInconsistentKeyFields(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "service",
+			"key": {
+				"type": "hex",
+				"privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47",
+				"mnemonic": "normal dune pole key case cradle unfold require tornado mercy hospital buyer",
+				"derivationPath": "m/44'/539'/0'/0/0"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeHex, accounts[0].Key.Type)
+	assert.Empty(t, accounts[0].Key.Mnemonic)
+
+	config.SetStrict(true)
+	defer config.SetStrict(false)
+
+	_, err = jsonAccounts.transformToConfig()
+	assert.EqualError(t, err, "account test has mnemonic, derivationPath set, which is not used by hex keys")
+}
+
+func Test_ConfigAccountKeychain(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "keychain",
+				"keychainService": "flow",
+				"keychainAccount": "test"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeKeychain, accounts[0].Key.Type)
+	assert.Equal(t, "flow", accounts[0].Key.KeychainService)
+	assert.Equal(t, "test", accounts[0].Key.KeychainAccount)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+}
+
+func Test_ConfigAccountBIP44Curve(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "bip44",
+				"signatureAlgorithm": "ECDSA_secp256k1",
+				"mnemonic": "version field tornado move level pretty inject stereo ten catalog salon swallow",
+				"derivationPath": "m/44'/539'/0'/0/0",
+				"curve": "secp256k1"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.CurveSecp256k1, accounts[0].Key.Curve)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+}
+
+func Test_ConfigAccountBIP44WithoutMnemonic(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "bip44",
+				"signatureAlgorithm": "ECDSA_P256",
+				"derivationPath": "m/44'/539'/0'/0/1"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeBip44, accounts[0].Key.Type)
+	assert.Empty(t, accounts[0].Key.Mnemonic)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+}
+
+func Test_ConfigAccountBIP44SigAlgo(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "bip44",
+				"mnemonic": "version field tornado move level pretty inject stereo ten catalog salon swallow"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.ECDSA_P256, accounts[0].Key.SigAlgo)
+
+	// the signature algorithm selecting the derivation curve is always saved
+	j := transformAccountsToJSON(accounts)
+	assert.Equal(t, "ECDSA_P256", j["test"].Advanced.Key.SigAlgo)
+
+	config.SetDefaultAlgorithms(crypto.ECDSA_secp256k1, crypto.SHA2_256)
+	defer config.SetDefaultAlgorithms(config.DefaultSigAlgo, config.DefaultHashAlgo)
+	accounts, err = jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.ECDSA_secp256k1, accounts[0].Key.SigAlgo)
+
+	j = transformAccountsToJSON(accounts)
+	assert.Equal(t, "ECDSA_secp256k1", j["test"].Advanced.Key.SigAlgo)
+	assert.Equal(t, "SHA2_256", j["test"].Advanced.Key.HashAlgo)
+}
+
+func Test_ConfigAccountBIP44Derivations(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "bip44",
+				"signatureAlgorithm": "ECDSA_P256",
+				"mnemonic": "version field tornado move level pretty inject stereo ten catalog salon swallow",
+				"derivationPath": "m/44'/539'/0'/0/0",
+				"derivations": [
+					{
+						"name": "test-secp256k1",
+						"index": 1,
+						"signatureAlgorithm": "ECDSA_secp256k1",
+						"hashAlgorithm": "SHA2_256",
+						"derivationPath": "m/44'/539'/0'/0/1"
+					},
+					{
+						"name": "other",
+						"address": "179b6b1cb6755e31",
+						"signatureAlgorithm": "ECDSA_secp256k1"
+					}
+				]
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []config.KeyDerivation{{
+		Name:           "test-secp256k1",
+		Index:          1,
+		SigAlgo:        crypto.ECDSA_secp256k1,
+		HashAlgo:       crypto.SHA2_256,
+		DerivationPath: "m/44'/539'/0'/0/1",
+	}, {
+		Name:    "other",
+		Address: flow.HexToAddress("179b6b1cb6755e31"),
+		SigAlgo: crypto.ECDSA_secp256k1,
+	}}, accounts[0].Key.Derivations)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+}
+
+func Test_ConfigAccountBIP44InvalidDerivations(t *testing.T) {
+	for _, test := range []struct {
+		derivation string
+		err        string
+	}{
+		{`{"signatureAlgorithm": "ECDSA_secp256k1"}`, "missing name of the derived key on account test"},
+		{`{"name": "other"}`, "invalid signature algorithm for the derived key other on account test"},
+		{`{"name": "other", "signatureAlgorithm": "ECDSA_secp256k1", "hashAlgorithm": "SHA1"}`, "invalid hash algorithm for the derived key other on account test"},
+	} {
+		var jsonAccounts jsonAccounts
+		err := json.Unmarshal([]byte(fmt.Sprintf(`{
+			"test": {
+				"address": "f8d6e0586b0a20c7",
+				"key": { "type": "bip44", "mnemonic": "version", "derivations": [%s] }
+			}
+		}`, test.derivation)), &jsonAccounts)
+		assert.NoError(t, err)
+
+		_, err = jsonAccounts.transformToConfig()
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func Test_ConfigAccountURL(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "url",
+				"location": "https://keys.example.com/test",
+				"authToken": "$KEY_TOKEN",
+				"headers": {
+					"X-Api-Key": "$KEY_API_KEY"
+				}
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeURL, accounts[0].Key.Type)
+	assert.Equal(t, "https://keys.example.com/test", accounts[0].Key.Location)
+	assert.Equal(t, map[string]string{"X-Api-Key": "$KEY_API_KEY"}, accounts[0].Key.Headers)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+}
+
+func Test_ConfigAccountSignatureFormat(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "remote-http",
+				"endpoint": "https://signer.example.com",
+				"resourceID": "test",
+				"signatureFormat": "der"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.SignatureFormatDER, accounts[0].Key.SignatureFormat)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+
+	b = []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "remote-http",
+				"endpoint": "https://signer.example.com",
+				"resourceID": "test",
+				"signatureFormat": "pem"
+			}
+		}
+	}`)
+	err = json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	_, err = jsonAccounts.transformToConfig()
+	assert.EqualError(t, err, "invalid signature format pem for remote signer key on account test, must be raw or der")
+}
+
+func Test_ConfigAccountCommand(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "command",
+				"command": ["vault", "kv", "get", "-field=key", "secret/flow"]
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeCommand, accounts[0].Key.Type)
+	assert.Equal(t, []string{"vault", "kv", "get", "-field=key", "secret/flow"}, accounts[0].Key.Command)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+
+	b = []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "command"
+			}
+		}
+	}`)
+	err = json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	_, err = jsonAccounts.transformToConfig()
+	assert.EqualError(t, err, "missing command printing the private key value for the account test")
+}
+
+func Test_ConfigAccountKeystore(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "keystore",
+				"location": "./keys.db",
+				"resourceID": "alice"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	accounts, err := jsonAccounts.transformToConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.KeyTypeKeystore, accounts[0].Key.Type)
+	assert.Equal(t, "./keys.db", accounts[0].Key.Location)
+	assert.Equal(t, "alice", accounts[0].Key.ResourceID)
+
+	j := transformAccountsToJSON(accounts)
+	x, _ := json.Marshal(j)
+	assert.JSONEq(t, string(b), string(x))
+
+	b = []byte(`{
+		"test": {
+			"address": "f8d6e0586b0a20c7",
+			"key": {
+				"type": "keystore",
+				"location": "./keys.db"
+			}
+		}
+	}`)
+	err = json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	_, err = jsonAccounts.transformToConfig()
+	assert.EqualError(t, err, "missing key ID in the keystore for the account test")
+}
+
+func Test_ConfigInvalidKeyType(t *testing.T) {
+	b := []byte(`{
+		"test": {
+			"address": "service",
+			"key": {
+				"type": "hexx",
+				"privateKey": "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47"
+			}
+		}
+	}`)
+
+	var jsonAccounts jsonAccounts
+	err := json.Unmarshal(b, &jsonAccounts)
+	assert.NoError(t, err)
+
+	_, err = jsonAccounts.transformToConfig()
+	assert.EqualError(t, err, `account test: invalid key type "hexx", did you mean "hex"?`)
+}
+
 func Test_ConfigInvalidAddress(t *testing.T) {
 	b := []byte(`{
 		"test": {
@@ -120,6 +120,11 @@ func (p *Parser) Serialize(conf *config.Config) ([]byte, error) {
 	return data, nil
 }
 
+// SerializeAccounts serializes the accounts to raw, in the same format they are stored under accounts in the configuration.
+func (p *Parser) SerializeAccounts(accounts config.Accounts) ([]byte, error) {
+	return json.MarshalIndent(transformAccountsToJSON(accounts), "", "\t")
+}
+
 // Deserialize configuration to config structure.
 func (p *Parser) Deserialize(raw []byte) (*config.Config, error) {
 	// check if old format of config and return an error
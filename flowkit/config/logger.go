@@ -0,0 +1,59 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-cli/flowkit/output"
+)
+
+// logger is used to report configuration issues that don't prevent the configuration from being used.
+var logger output.Logger = output.NewStdoutLogger(output.NoneLog)
+
+// strict turns warnings about likely configuration mistakes into errors.
+var strict = false
+
+// SetLogger sets the logger used to report configuration warnings.
+//
+// Warnings are discarded until a logger is set.
+func SetLogger(l output.Logger) {
+	logger = l
+}
+
+// Warn reports a configuration warning using the logger.
+func Warn(msg string) {
+	logger.Info(fmt.Sprintf("%s %s", output.WarningEmoji(), msg))
+}
+
+// SetStrict enables or disables strict mode, in which likely configuration mistakes are errors instead of warnings.
+func SetStrict(enabled bool) {
+	strict = enabled
+}
+
+// WarnStrict reports a configuration warning or returns it as an error in strict mode.
+func WarnStrict(msg string) error {
+	if strict {
+		return errors.New(msg)
+	}
+
+	Warn(msg)
+	return nil
+}
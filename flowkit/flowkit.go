@@ -232,7 +232,7 @@ func (f *Flowkit) prepareTransaction(
 	}
 
 	tx.SetBlockReference(block)
-	if err = tx.SetProposer(proposer, account.Key.Index()); err != nil {
+	if err = tx.SetProposer(proposer, account.RoleKeyIndex(accounts.SignRoleProposer)); err != nil {
 		return nil, err
 	}
 
@@ -634,6 +634,7 @@ func (f *Flowkit) DerivePrivateKeyFromMnemonic(
 	sigAlgo crypto.SignatureAlgorithm,
 	derivationPath string,
 ) (crypto.PrivateKey, error) {
+	mnemonic = accounts.NormalizeMnemonic(mnemonic)
 	if !bip39.IsMnemonicValid(mnemonic) {
 		return nil, fmt.Errorf("invalid mnemonic")
 	}
@@ -1009,7 +1010,7 @@ func (f *Flowkit) SendTransaction(
 	tx, err := f.BuildTransaction(
 		ctx,
 		accounts.AddressRoles(),
-		accounts.Proposer.Key.Index(),
+		accounts.ProposerKeyIndex(),
 		script,
 		gasLimit,
 	)
@@ -494,11 +494,26 @@ func PrivKeys() []crypto.PrivateKey {
 			crypto.ECDSA_P256,
 			[]byte(fmt.Sprintf("seedseedseedseedseedseedseedseedseedseedseedseed%d", x)),
 		)
+		pk.PublicKey()
 		privKeys = append(privKeys, pk)
 	}
 	return privKeys
 }
 
+// DecodePrivKey decodes the hex encoded private key, panicking if the key is invalid.
+//
+// The public key is computed and stored in the private key on the first PublicKey call without any
+// synchronisation, so it's computed here to allow sharing the key between signers used concurrently.
+// PrivKeys computes the public keys for the same reason.
+func DecodePrivKey(sigAlgo crypto.SignatureAlgorithm, keyHex string) crypto.PrivateKey {
+	pk, err := crypto.DecodePrivateKeyHex(sigAlgo, keyHex)
+	if err != nil {
+		panic(err)
+	}
+	pk.PublicKey()
+	return pk
+}
+
 func SigAlgos() []crypto.SignatureAlgorithm {
 	var sigAlgos []crypto.SignatureAlgorithm
 	privKeys := PrivKeys()
@@ -22,6 +22,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"strings"
 
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
@@ -29,8 +30,10 @@ import (
 	"github.com/onflow/cadence/runtime/parser"
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/templates"
+	"golang.org/x/exp/slices"
 
 	"github.com/onflow/flow-cli/flowkit/accounts"
+	"github.com/onflow/flow-cli/flowkit/config"
 )
 
 // New create new instance of transaction.
@@ -93,6 +96,68 @@ func NewRemoveAccountContract(signer *accounts.Account, name string) (*Transacti
 	)
 }
 
+// NewAddAccountKey creates new transaction to add the key to the signer account.
+func NewAddAccountKey(signer *accounts.Account, key *flow.AccountKey) (*Transaction, error) {
+	template, err := templates.AddAccountKey(signer.Address, key)
+	if err != nil {
+		return nil, err
+	}
+	return newFromTemplate(template, signer)
+}
+
+// NewRevokeAccountKey creates new transaction to revoke the key at the index from the signer account.
+func NewRevokeAccountKey(signer *accounts.Account, keyIndex int) (*Transaction, error) {
+	return newFromTemplate(
+		templates.RemoveAccountKey(signer.Address, keyIndex),
+		signer,
+	)
+}
+
+// NewKMSKeyRotation creates the transactions rotating the KMS key of the signer account to a new KMS key.
+//
+// The add transaction adds the key at the new resource ID to the account with the weight and algorithms
+// of the old key, and the revoke transaction revokes the old key. Both are signed with the old key, so the
+// revoke transaction should only be sent once the add transaction is sealed. The new key must be accessible,
+// otherwise no transactions are created.
+//
+// The new key index must be the index at which the network adds the key, which is the number of keys on the
+// account including revoked keys. The returned key uses that index and must replace the old key in the
+// configuration once the add transaction is sealed, since the old key index can't sign after it's revoked.
+func NewKMSKeyRotation(
+	ctx context.Context,
+	signer *accounts.Account,
+	oldResourceID string,
+	newResourceID string,
+	newKeyIndex int,
+) (add *Transaction, revoke *Transaction, newKey accounts.Key, err error) {
+	oldKey := signer.Key
+	if strings.TrimSpace(oldKey.ToConfig().ResourceID) != strings.TrimSpace(oldResourceID) {
+		return nil, nil, nil, fmt.Errorf("account %s is not using the KMS key %s", signer.Name, oldResourceID)
+	}
+
+	newKey, err = accounts.NewRotatedKMSKey(ctx, oldKey, newResourceID, newKeyIndex)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	flowKey, err := newKey.ToFlowAccountKey()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	add, err = NewAddAccountKey(signer, flowKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	revoke, err = NewRevokeAccountKey(signer, oldKey.Index())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return add, revoke, newKey, nil
+}
+
 // addAccountContractWithArgs contains logic to build a transaction and include the contract code
 // as well as possible init arguments.
 func addAccountContractWithArgs(
@@ -203,6 +268,10 @@ func (t *Transaction) SetSigner(account *accounts.Account) error {
 		return fmt.Errorf("signer account missing the key")
 	}
 
+	if !account.HasAddress() {
+		return fmt.Errorf("address of signer account %s is not set, set it with SetAddress once the account is created", account.Name)
+	}
+
 	err := account.Key.Validate()
 	if err != nil {
 		return err
@@ -334,28 +403,66 @@ func (t *Transaction) AddAuthorizers(authorizers []flow.Address) (*Transaction,
 }
 
 // Sign signs transaction using signer account.
+//
+// If the signer is the proposer and proposes with a different key index than used for its other role,
+// the transaction is signed with the proposal key as well.
 func (t *Transaction) Sign() (*Transaction, error) {
-	keyIndex := t.signer.Key.Index()
-	signer, err := t.signer.Key.Signer(context.Background())
+	role := t.signerRole()
+
+	// the payload must be signed before the envelope, which includes the payload signatures
+	isProposer := t.tx.ProposalKey.Address == t.signer.Address
+	proposerIndex := t.signer.RoleKeyIndex(accounts.SignRoleProposer)
+	if role != accounts.SignRoleProposer && isProposer && proposerIndex != t.signer.RoleKeyIndex(role) {
+		err := t.signer.SignTransaction(context.Background(), t.tx, accounts.SignRoleProposer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := t.signer.SignTransaction(context.Background(), t.tx, role)
 	if err != nil {
 		return nil, err
 	}
 
-	if t.shouldSignEnvelope() {
-		err = t.tx.SignEnvelope(t.signer.Address, keyIndex, signer)
-		if err != nil {
-			return nil, fmt.Errorf("failed to sign transaction: %s", err)
-		}
-	} else {
-		err = t.tx.SignPayload(t.signer.Address, keyIndex, signer)
+	// the proposal key doesn't need the weight threshold, but authorizers and the payer do
+	weight := t.SignedWeight()
+	if role != accounts.SignRoleProposer && weight < flow.AccountKeyWeightThreshold {
+		err := config.WarnStrict(fmt.Sprintf(
+			"account %s signed the transaction with weight %d, which is below the threshold of %d, the transaction will be rejected unless other keys of the account sign it",
+			t.signer.Name,
+			weight,
+			flow.AccountKeyWeightThreshold,
+		))
 		if err != nil {
-			return nil, fmt.Errorf("failed to sign transaction: %s", err)
+			return nil, err
 		}
 	}
 
 	return t, nil
 }
 
+// SignedWeight returns the weight of the signature attached by the signer account for its role.
+func (t *Transaction) SignedWeight() int {
+	if t.signer == nil {
+		return 0
+	}
+	return t.signer.SignedWeight(t.tx, t.signerRole())
+}
+
+// signerRole returns the role in which the signer signs the transaction.
+func (t *Transaction) signerRole() accounts.SignRole {
+	if t.shouldSignEnvelope() {
+		return accounts.SignRolePayer
+	}
+
+	isProposer := t.tx.ProposalKey.Address == t.signer.Address
+	if isProposer && !slices.Contains(t.tx.Authorizers, t.signer.Address) {
+		return accounts.SignRoleProposer
+	}
+
+	return accounts.SignRoleAuthorizer
+}
+
 // shouldSignEnvelope checks if signer should sign envelope or payload
 func (t *Transaction) shouldSignEnvelope() bool {
 	return t.signer.Address == t.tx.Payer
@@ -394,6 +501,11 @@ func (t AccountRoles) AddressRoles() AddressesRoles {
 	}
 }
 
+// ProposerKeyIndex returns the key index the proposer uses to propose.
+func (t AccountRoles) ProposerKeyIndex() int {
+	return t.Proposer.RoleKeyIndex(accounts.SignRoleProposer)
+}
+
 // Signers for signing the transaction, detect if all accounts are same so only return the one account.
 func (t AccountRoles) Signers() []*accounts.Account {
 	// build only unique accounts to sign, it's important payer account is last
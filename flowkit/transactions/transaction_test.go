@@ -19,15 +19,18 @@
 package transactions_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go-sdk/templates"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/onflow/flow-cli/flowkit/accounts"
+	"github.com/onflow/flow-cli/flowkit/config"
 	"github.com/onflow/flow-cli/flowkit/tests"
 	"github.com/onflow/flow-cli/flowkit/transactions"
 )
@@ -85,3 +88,111 @@ func TestNew(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, signed.FlowTransaction().EnvelopeSignatures, 1)
 }
+
+func Test_NewKMSKeyRotation(t *testing.T) {
+	const oldID = "arn:aws:kms:us-east-1:111122223333:key/old"
+	const newID = "arn:aws:kms:us-east-1:111122223333:key/new"
+
+	pkey := tests.DecodePrivKey(crypto.ECDSA_P256, "dd72967fd2bd75234ae9037dd4694c1f00baad63a10c35172bf65fbb8ad74b47")
+
+	accs, err := accounts.FromConfig(&config.Config{
+		Accounts: config.Accounts{{
+			Name:    "kms",
+			Address: flow.HexToAddress("0x01"),
+			Key: config.AccountKey{
+				Type:       config.KeyTypeKMS,
+				Index:      2,
+				Weight:     1000,
+				SigAlgo:    crypto.ECDSA_P256,
+				HashAlgo:   crypto.SHA3_256,
+				ResourceID: oldID,
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	signer := &accs[0]
+
+	t.Run("Rotate", func(t *testing.T) {
+		accounts.RegisterKMSProvider(config.KeyTypeAWSKMS, func(_ context.Context, _ any, hashAlgo crypto.HashAlgorithm) (crypto.Signer, error) {
+			return crypto.NewInMemorySigner(pkey, hashAlgo)
+		})
+		defer accounts.RegisterKMSProvider(config.KeyTypeAWSKMS, nil)
+
+		add, revoke, newKey, err := transactions.NewKMSKeyRotation(context.Background(), signer, oldID, newID, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, newKey.Index())
+		assert.Equal(t, newID, newKey.ToConfig().ResourceID)
+
+		assert.Equal(t, []flow.Address{signer.Address}, add.FlowTransaction().Authorizers)
+		expected, err := templates.AddAccountKey(signer.Address, &flow.AccountKey{
+			Index:     3,
+			PublicKey: pkey.PublicKey(),
+			SigAlgo:   crypto.ECDSA_P256,
+			HashAlgo:  crypto.SHA3_256,
+			Weight:    1000,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, expected.Arguments, add.FlowTransaction().Arguments)
+
+		assert.Equal(t, []flow.Address{signer.Address}, revoke.FlowTransaction().Authorizers)
+		assert.Equal(t, templates.RemoveAccountKey(signer.Address, 2).Arguments, revoke.FlowTransaction().Arguments)
+	})
+
+	t.Run("Fail new key not accessible", func(t *testing.T) {
+		_, _, _, err := transactions.NewKMSKeyRotation(context.Background(), signer, oldID, newID, 3)
+		assert.EqualError(t, err, "new KMS key arn:aws:kms:us-east-1:111122223333:key/new is not accessible: aws-kms keys are not supported, register a signer using RegisterKMSProvider")
+	})
+
+	t.Run("Fail wrong old key", func(t *testing.T) {
+		_, _, _, err := transactions.NewKMSKeyRotation(context.Background(), signer, newID, oldID, 3)
+		assert.EqualError(t, err, "account kms is not using the KMS key arn:aws:kms:us-east-1:111122223333:key/new")
+	})
+}
+
+func Test_SignPartialWeight(t *testing.T) {
+	pkey := tests.PrivKeys()[0]
+
+	accs, err := accounts.FromConfig(&config.Config{
+		Accounts: config.Accounts{{
+			Name:    "partial",
+			Address: flow.HexToAddress("0x01"),
+			Key: config.AccountKey{
+				Type:       config.KeyTypeHex,
+				Weight:     500,
+				SigAlgo:    crypto.ECDSA_P256,
+				HashAlgo:   crypto.SHA3_256,
+				PrivateKey: pkey,
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	signer := &accs[0]
+
+	tx, err := transactions.NewRemoveAccountContract(signer, "Hello")
+	assert.NoError(t, err)
+	_ = tx.SetProposer(tests.NewAccountWithAddress(signer.Address.String()), 0)
+
+	signed, err := tx.Sign()
+	assert.NoError(t, err)
+	assert.Equal(t, 500, signed.SignedWeight())
+
+	config.SetStrict(true)
+	defer config.SetStrict(false)
+
+	_, err = tx.Sign()
+	assert.EqualError(t, err, "account partial signed the transaction with weight 500, which is below the threshold of 1000, the transaction will be rejected unless other keys of the account sign it")
+
+	t.Run("Proposer index", func(t *testing.T) {
+		signer.RoleKeyIndices = map[accounts.SignRole]int{accounts.SignRoleProposer: 1}
+		defer func() { signer.RoleKeyIndices = nil }()
+
+		tx, err := transactions.NewRemoveAccountContract(signer, "Hello")
+		assert.NoError(t, err)
+		_ = tx.SetProposer(tests.NewAccountWithAddress(signer.Address.String()), 1)
+		tx.SetPayer(flow.HexToAddress("0x02"))
+
+		_, err = tx.Sign()
+		assert.EqualError(t, err, "account partial signed the transaction with weight 500, which is below the threshold of 1000, the transaction will be rejected unless other keys of the account sign it")
+		assert.Len(t, tx.FlowTransaction().PayloadSignatures, 2)
+	})
+}
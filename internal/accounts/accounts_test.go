@@ -32,6 +32,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-cli/flowkit"
+	"github.com/onflow/flow-cli/flowkit/config"
 	"github.com/onflow/flow-cli/flowkit/tests"
 	"github.com/onflow/flow-cli/internal/command"
 	"github.com/onflow/flow-cli/internal/util"
@@ -266,6 +267,84 @@ func Test_Get(t *testing.T) {
 	})
 }
 
+func Test_Info(t *testing.T) {
+	_, state, _ := util.TestMocks(t)
+
+	t.Run("Success achievable", func(t *testing.T) {
+		result, err := info([]string{"emulator-account"}, command.GlobalFlags{}, util.NoLogger, nil, state)
+		require.NoError(t, err)
+
+		infoResult := result.(*accountInfoResult)
+		assert.Equal(t, []accounts.AuthKey{{Account: "emulator-account", Index: 0, Weight: 1000}}, infoResult.plan.Keys)
+		assert.Contains(t, result.String(), "Full authorization is reached with the listed keys")
+		assert.Equal(t,
+			"Name: emulator-account, Address: 0xf8d6e0586b0a20c7, Authorization Keys: emulator-account:0(1000), Weight: 1000, Achievable: true",
+			result.Oneliner(),
+		)
+	})
+
+	t.Run("Success not achievable", func(t *testing.T) {
+		address := flow.HexToAddress("0x02")
+		accs, err := accounts.FromConfig(&config.Config{
+			Accounts: config.Accounts{
+				multisigAccountConfig("alice", address, 0, 500, tests.PrivKeys()[0]),
+				multisigAccountConfig("bob", address, 1, 400, tests.PrivKeys()[1]),
+			},
+		})
+		require.NoError(t, err)
+		for i := range accs {
+			state.Accounts().AddOrUpdate(&accs[i])
+		}
+
+		result, err := info([]string{"bob"}, command.GlobalFlags{}, util.NoLogger, nil, state)
+		require.NoError(t, err)
+
+		assert.Equal(t, strings.TrimPrefix(`
+Name			 bob
+Address			 0x0000000000000002
+Key Type		 hex
+Key Index		 1
+Key Weight		 400
+Signature Algorithm	 ECDSA_P256
+Hash Algorithm		 SHA3_256
+
+Authorization
+1.		Key 0 of alice	 weight 500
+2.		Key 1 of bob	 weight 400
+Total Weight	 900
+Full authorization is not reached, add accounts with the keys for the missing weight of 100
+`, "\n"), result.String())
+		assert.Equal(t,
+			"Name: bob, Address: 0x0000000000000002, Authorization Keys: alice:0(500) bob:1(400), Weight: 900, Achievable: false",
+			result.Oneliner(),
+		)
+
+		authorization := result.JSON().(map[string]any)["authorization"].(map[string]any)
+		assert.Equal(t, 900, authorization["weight"])
+		assert.Equal(t, false, authorization["achievable"])
+	})
+
+	t.Run("Fail unknown account", func(t *testing.T) {
+		_, err := info([]string{"unknown"}, command.GlobalFlags{}, util.NoLogger, nil, state)
+		assert.EqualError(t, err, "could not find account with name unknown in the configuration")
+	})
+}
+
+func multisigAccountConfig(name string, address flow.Address, index int, weight int, pkey crypto.PrivateKey) config.Account {
+	return config.Account{
+		Name:    name,
+		Address: address,
+		Key: config.AccountKey{
+			Type:       config.KeyTypeHex,
+			Index:      index,
+			Weight:     weight,
+			SigAlgo:    pkey.Algorithm(),
+			HashAlgo:   crypto.SHA3_256,
+			PrivateKey: pkey,
+		},
+	}
+}
+
 func Test_Result(t *testing.T) {
 	pkey, _ := crypto.DecodePublicKeyHex(crypto.ECDSA_P256, "a60b9c10a39070806d37d8f0e6be081e7af2d18cd92ee1bd850d10c994d61d538d2693eebe8faa94fea59ee579ea65a70ed897b05126e508e74f55b8669eec6b")
 	account := &flow.Account{
@@ -0,0 +1,153 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accounts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	flowsdk "github.com/onflow/flow-go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-cli/flowkit"
+	"github.com/onflow/flow-cli/flowkit/accounts"
+	"github.com/onflow/flow-cli/flowkit/output"
+	"github.com/onflow/flow-cli/internal/command"
+	"github.com/onflow/flow-cli/internal/util"
+)
+
+type flagsInfo struct{}
+
+var infoFlags = flagsInfo{}
+
+var infoCommand = &command.Command{
+	Cmd: &cobra.Command{
+		Use:     "info <name>",
+		Short:   "Shows the configured account and the keys that sign to authorize it",
+		Example: "flow accounts info emulator-account",
+		Args:    cobra.ExactArgs(1),
+	},
+	Flags: &infoFlags,
+	RunS:  info,
+}
+
+func info(
+	args []string,
+	_ command.GlobalFlags,
+	_ output.Logger,
+	_ flowkit.Services,
+	state *flowkit.State,
+) (command.Result, error) {
+	account, err := state.Accounts().ByName(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// accounts sharing the address hold other keys signing together with the account key
+	plan, err := state.Accounts().AuthorizationPlan(account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountInfoResult{
+		account: account,
+		plan:    plan,
+	}, nil
+}
+
+type accountInfoResult struct {
+	account *accounts.Account
+	plan    *accounts.AuthorizationPlan
+}
+
+func (r *accountInfoResult) JSON() any {
+	keys := make([]map[string]any, 0, len(r.plan.Keys))
+	for _, key := range r.plan.Keys {
+		keys = append(keys, map[string]any{
+			"account": key.Account,
+			"index":   key.Index,
+			"weight":  key.Weight,
+		})
+	}
+
+	return map[string]any{
+		"name":    r.account.Name,
+		"address": r.account.FormattedAddress(),
+		"key": map[string]any{
+			"type":               r.account.Key.Type(),
+			"index":              r.account.Key.Index(),
+			"weight":             r.account.Key.Weight(),
+			"signatureAlgorithm": r.account.Key.SigAlgo().String(),
+			"hashAlgorithm":      r.account.Key.HashAlgo().String(),
+		},
+		"authorization": map[string]any{
+			"keys":       keys,
+			"weight":     r.plan.Weight,
+			"achievable": r.plan.Achievable(),
+		},
+	}
+}
+
+func (r *accountInfoResult) String() string {
+	var b bytes.Buffer
+	writer := util.CreateTabWriter(&b)
+
+	_, _ = fmt.Fprintf(writer, "Name\t %s\n", r.account.Name)
+	_, _ = fmt.Fprintf(writer, "Address\t %s\n", r.account.FormattedAddress())
+	_, _ = fmt.Fprintf(writer, "Key Type\t %s\n", r.account.Key.Type())
+	_, _ = fmt.Fprintf(writer, "Key Index\t %d\n", r.account.Key.Index())
+	_, _ = fmt.Fprintf(writer, "Key Weight\t %d\n", r.account.Key.Weight())
+	_, _ = fmt.Fprintf(writer, "Signature Algorithm\t %s\n", r.account.Key.SigAlgo())
+	_, _ = fmt.Fprintf(writer, "Hash Algorithm\t %s\n", r.account.Key.HashAlgo())
+
+	_, _ = fmt.Fprintf(writer, "\nAuthorization\n")
+	for i, key := range r.plan.Keys {
+		_, _ = fmt.Fprintf(writer, "%d.\tKey %d of %s\t weight %d\n", i+1, key.Index, key.Account, key.Weight)
+	}
+	_, _ = fmt.Fprintf(writer, "Total Weight\t %d\n", r.plan.Weight)
+	if r.plan.Achievable() {
+		_, _ = fmt.Fprintf(writer, "Full authorization is reached with the listed keys\n")
+	} else {
+		_, _ = fmt.Fprintf(
+			writer,
+			"Full authorization is not reached, add accounts with the keys for the missing weight of %d\n",
+			flowsdk.AccountKeyWeightThreshold-r.plan.Weight,
+		)
+	}
+
+	_ = writer.Flush()
+	return b.String()
+}
+
+func (r *accountInfoResult) Oneliner() string {
+	keys := make([]string, 0, len(r.plan.Keys))
+	for _, key := range r.plan.Keys {
+		keys = append(keys, fmt.Sprintf("%s:%d(%d)", key.Account, key.Index, key.Weight))
+	}
+
+	return fmt.Sprintf(
+		"Name: %s, Address: %s, Authorization Keys: %s, Weight: %d, Achievable: %t",
+		r.account.Name,
+		r.account.FormattedAddress(),
+		strings.Join(keys, " "),
+		r.plan.Weight,
+		r.plan.Achievable(),
+	)
+}
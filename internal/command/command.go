@@ -99,6 +99,10 @@ func (c Command) AddToParent(parent *cobra.Command) {
 			defer sentry.Recover()
 		}
 
+		logger := createLogger(Flags.Log, Flags.Format)
+		config.SetLogger(logger)
+		config.SetStrict(Flags.StrictConfig)
+
 		// initialize file loader used in commands
 		loader := &afero.Afero{Fs: afero.NewOsFs()}
 
@@ -114,8 +118,6 @@ func (c Command) AddToParent(parent *cobra.Command) {
 		clientGateway, err := createGateway(*network)
 		handleError("Gateway Error", err)
 
-		logger := createLogger(Flags.Log, Flags.Format)
-
 		// initialize services
 		flow := flowkit.NewFlowkit(state, *network, clientGateway, logger)
 
@@ -355,4 +357,5 @@ type GlobalFlags struct {
 	Yes              bool
 	ConfigPaths      []string
 	SkipVersionCheck bool
+	StrictConfig     bool
 }
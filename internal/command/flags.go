@@ -41,6 +41,7 @@ var Flags = GlobalFlags{
 	Yes:              false,
 	ConfigPaths:      config.DefaultPaths(),
 	SkipVersionCheck: false,
+	StrictConfig:     false,
 }
 
 // InitFlags init all the global persistent flags.
@@ -124,6 +125,14 @@ func InitFlags(cmd *cobra.Command) {
 		Flags.SkipVersionCheck,
 		"Skip version check during start up",
 	)
+
+	cmd.PersistentFlags().BoolVarP(
+		&Flags.StrictConfig,
+		"strict-config",
+		"",
+		Flags.StrictConfig,
+		"Fail on configuration mistakes instead of warning about them",
+	)
 }
 
 // bindFlags bind all the flags needed.
@@ -34,7 +34,8 @@ import (
 )
 
 type flagsGenerate struct {
-	Signer string `default:"emulator-account" flag:"signer" info:"name of the account used to sign"`
+	Signer    string `default:"emulator-account" flag:"signer" info:"name of the account used to sign"`
+	DomainTag string `default:"" flag:"domain-tag" info:"domain tag prepended to the message before signing, such as FLOW-V0.0-user"`
 }
 
 var generateFlags = flagsGenerate{}
@@ -64,11 +65,23 @@ func sign(
 		return nil, err
 	}
 
+	if !acc.Key.SupportsMessageSigning() {
+		return nil, fmt.Errorf("key of account %s does not support signing messages", accountName)
+	}
+
 	s, err := acc.Key.Signer(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
+	if generateFlags.DomainTag != "" {
+		tag, err := accounts.NewDomainTag(generateFlags.DomainTag)
+		if err != nil {
+			return nil, err
+		}
+		s = accounts.NewDomainTagSigner(s, tag)
+	}
+
 	signed, err := s.Sign(message)
 	if err != nil {
 		return nil, err
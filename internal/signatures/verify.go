@@ -28,14 +28,16 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/onflow/flow-cli/flowkit"
+	"github.com/onflow/flow-cli/flowkit/accounts"
 	"github.com/onflow/flow-cli/flowkit/output"
 	"github.com/onflow/flow-cli/internal/command"
 	"github.com/onflow/flow-cli/internal/util"
 )
 
 type flagsVerify struct {
-	SigAlgo  string `flag:"sig-algo" default:"ECDSA_P256" info:"Signature algorithm used to create the public key"`
-	HashAlgo string `flag:"hash-algo" default:"SHA3_256" info:"Hashing algorithm used to create signature"`
+	SigAlgo   string `flag:"sig-algo" default:"ECDSA_P256" info:"Signature algorithm used to create the public key"`
+	HashAlgo  string `flag:"hash-algo" default:"SHA3_256" info:"Hashing algorithm used to create signature"`
+	DomainTag string `flag:"domain-tag" default:"" info:"domain tag prepended to the message before signing, such as FLOW-V0.0-user"`
 }
 
 var verifyFlags = flagsVerify{}
@@ -83,7 +85,16 @@ func verify(
 		return nil, err
 	}
 
-	valid, err := pkey.Verify(sig, message, hasher)
+	signed := message
+	if verifyFlags.DomainTag != "" {
+		tag, err := accounts.NewDomainTag(verifyFlags.DomainTag)
+		if err != nil {
+			return nil, err
+		}
+		signed = append(tag[:], message...)
+	}
+
+	valid, err := pkey.Verify(sig, signed, hasher)
 	if err != nil {
 		return nil, err
 	}